@@ -1,17 +1,28 @@
 package channels
 
 import (
+	"context"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	internalTime "github.com/jonabc/channels/internal/time"
 	"github.com/jonabc/channels/providers"
 )
 
 // BatchConfig contains user configurable options for the Batch functions
 type BatchConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[BatchStats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[BatchStats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	clock                clock.Clock
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	emitEmpty            bool
 }
 
 // Batch N values from the input channel into an array of N values in the output channel.
@@ -22,12 +33,18 @@ func Batch[T any](inc <-chan T, batchSize int, maxDelay time.Duration, opts ...O
 	cfg := parseOpts(opts...)
 
 	outc := make(chan []T, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(BatchOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
 	buffer := make([]T, 0, batchSize)
 
-	timer := internalTime.NewTimer(maxDelay)
+	timer := internalTime.NewTimer(clk, maxDelay)
 	timer.Stop()
 
 	var batchStart time.Time
@@ -38,23 +55,34 @@ func Batch[T any](inc <-chan T, batchSize int, maxDelay time.Duration, opts ...O
 			return
 		}
 
-		duration := time.Since(batchStart)
+		duration := clk.Now().Sub(batchStart)
 		batchSize := len(buffer)
 
 		keys := make([]T, batchSize)
 		copy(keys, buffer)
-		outc <- keys
 		buffer = buffer[:0]
-		tryProvideStats(BatchStats{Duration: duration, BatchSize: uint(batchSize), QueueLength: len(inc)}, statsProvider)
+		if sendWait, ok := timedSend(ctx, clk, outc, keys); ok {
+			tryProvideStats(BatchStats{Operator: operator, Name: name, Duration: duration, BatchSize: uint(batchSize), QueueLength: len(inc), SendWait: sendWait, OutputLength: len(outc)}, statsProvider)
+		}
 	}
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
 		defer timer.Stop()
+		defer panics.handle()
 
 		for {
+			if ctx.Err() != nil {
+				return
+			}
+
 			select {
+			case <-ctx.Done():
+				return
 			case in, ok := <-inc:
 				if !ok {
 					publishAndReset()
@@ -63,7 +91,7 @@ func Batch[T any](inc <-chan T, batchSize int, maxDelay time.Duration, opts ...O
 
 				if len(buffer) == 0 {
 					timer.Reset(maxDelay)
-					batchStart = time.Now()
+					batchStart = clk.Now()
 				}
 
 				buffer = append(buffer, in)
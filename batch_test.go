@@ -1,12 +1,14 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
@@ -177,3 +179,80 @@ func TestBatchProviderOptionWithStatsReporting(t *testing.T) {
 	require.Equal(t, uint(1), stats[0].BatchSize)
 	require.Equal(t, 0, stats[0].QueueLength)
 }
+
+func TestBatchStatsReportSendWait(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := &steppingClock{Manual: clock.NewManual(time.Now()), step: time.Second}
+	provider, receiver := providers.NewProvider[channels.BatchStats](1)
+	defer provider.Close()
+
+	out := channels.Batch(in, 1, time.Hour,
+		channels.ChannelCapacityOption[channels.BatchConfig](1),
+		channels.ClockOption[channels.BatchConfig](clk),
+		channels.BatchStatsProviderOption(provider),
+	)
+
+	in <- 1
+	stats := <-receiver.Channel()
+	require.Equal(t, time.Second, stats.SendWait)
+	require.Equal(t, 1, stats.OutputLength)
+	require.Equal(t, []int{1}, <-out)
+}
+func TestBatchContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Batch(in, 1, 0,
+		channels.ContextOption[channels.BatchConfig](ctx),
+		channels.CancellationProviderOption[channels.BatchConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, []int{1}, <-out)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestBatchClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	provider, receiver := providers.NewProvider[channels.BatchStats](1)
+	defer provider.Close()
+
+	out := channels.Batch(in, 5, time.Minute,
+		channels.ClockOption[channels.BatchConfig](clk),
+		channels.BatchStatsProviderOption(provider),
+	)
+
+	in <- 1
+	in <- 2
+	clk.BlockUntil(1)
+
+	clk.Advance(59 * time.Second)
+	require.Len(t, out, 0)
+
+	clk.Advance(time.Second)
+	require.Equal(t, []int{1, 2}, <-out)
+
+	stats := <-receiver.Channel()
+	require.Equal(t, time.Minute, stats.Duration)
+	require.Equal(t, uint(2), stats.BatchSize)
+}
@@ -0,0 +1,150 @@
+package channels
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+type BroadcastMode byte
+
+const (
+	// Values wait until the output channel can be written to, applying
+	// backpressure to the input channel and every other output channel.
+	BlockOnFullOutput BroadcastMode = iota
+	// Values are dropped when the output channel is full.
+	DropOnFullOutput
+	// Values are buffered when the output channel is full, and dropped when the
+	// buffer is full.  Buffered values are written in order.
+	BufferOnFullOutput
+)
+
+// BroadcastPolicy configures how Broadcast writes values to an output channel.
+// Limit is the number of values buffered for the output channel with
+// BufferOnFullOutput, and must be at least 1.
+type BroadcastPolicy struct {
+	Mode  BroadcastMode
+	Limit int
+}
+
+type BroadcastConfig struct {
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[BroadcastStats]
+	capacities           []int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	policies             []BroadcastPolicy
+	clock                clock.Clock
+}
+
+// Broadcast reads values from the input channel and writes each value to every one
+// of `count` output channels.  How each output channel is written to is configured
+// with BroadcastPoliciesOption: by default a value waits until every output channel
+// can be written to, so a slow reader of one output channel slows down every output
+// channel.  With DropOnFullOutput or BufferOnFullOutput, values for a slow output
+// channel are dropped instead of blocking the other output channels.  Each output
+// channel is unbuffered by default, and will be closed after the input channel is
+// closed and every buffered value has been written.
+func Broadcast[T any](inc <-chan T, count int, opts ...Option[BroadcastConfig]) []<-chan T {
+	cfg := parseOpts(opts...)
+
+	// values are written to the output channel, or to the output channel's buffer
+	writeOutc := make([]chan<- T, count)
+	readOutc := make([]<-chan T, count)
+	policies := make([]BroadcastPolicy, count)
+	operator := cfg.operator.or(BroadcastOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		capacity := 0
+		if i < len(cfg.capacities) {
+			capacity = cfg.capacities[i]
+		}
+		if i < len(cfg.policies) {
+			policies[i] = cfg.policies[i]
+		}
+
+		c := make(chan T, capacity)
+		readOutc[i] = c
+		if policies[i].Mode != BufferOnFullOutput {
+			writeOutc[i] = c
+			continue
+		}
+
+		buffer := make(chan T, max(policies[i].Limit, 1))
+		writeOutc[i] = buffer
+
+		wg.Add(1)
+		go func(buffer <-chan T, outc chan<- T) {
+			defer wg.Done()
+			defer close(outc)
+			defer panics.handle()
+
+			for {
+				val, ok := receive(ctx, buffer)
+				if !ok || !send(ctx, outc, val) {
+					return
+				}
+			}
+		}(buffer, c)
+	}
+
+	dropped := make([]uint, count)
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer wg.Wait()
+		defer func() {
+			for _, c := range writeOutc {
+				close(c)
+			}
+		}()
+		defer panics.handle()
+
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok {
+				return
+			}
+
+			start := clk.Now()
+			for i, c := range writeOutc {
+				if policies[i].Mode == BlockOnFullOutput {
+					if !send(ctx, c, in) {
+						return
+					}
+					continue
+				}
+
+				select {
+				case c <- in:
+				default:
+					dropped[i]++
+				}
+			}
+
+			if statsProvider != nil {
+				tryProvideStats(BroadcastStats{Operator: operator, Name: name, Duration: clk.Now().Sub(start), Dropped: append([]uint(nil), dropped...), QueueLength: len(inc)}, statsProvider)
+			}
+		}
+	}()
+
+	return readOutc
+}
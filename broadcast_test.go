@@ -0,0 +1,138 @@
+package channels_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcast(t *testing.T) {
+	t.Parallel()
+
+	inc := make(chan int)
+	outs := channels.Broadcast(inc, 3)
+	require.Len(t, outs, 3)
+
+	var wg sync.WaitGroup
+	results := make([][]int, len(outs))
+	for i, out := range outs {
+		require.Equal(t, 0, cap(out))
+
+		wg.Add(1)
+		go func(i int, out <-chan int) {
+			defer wg.Done()
+			for val := range out {
+				results[i] = append(results[i], val)
+			}
+		}(i, out)
+	}
+
+	for i := 1; i <= 5; i++ {
+		inc <- i
+	}
+	close(inc)
+	wg.Wait()
+
+	for _, result := range results {
+		require.Equal(t, []int{1, 2, 3, 4, 5}, result)
+	}
+}
+
+func TestBroadcastDropOnFullOutput(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.BroadcastStats](10)
+	defer provider.Close()
+
+	inc := make(chan int)
+	outs := channels.Broadcast(inc, 2,
+		channels.MultiChannelCapacitiesOption[channels.BroadcastConfig]([]int{0, 1}),
+		channels.BroadcastPoliciesOption([]channels.BroadcastPolicy{
+			{Mode: channels.BlockOnFullOutput},
+			{Mode: channels.DropOnFullOutput},
+		}),
+		channels.BroadcastStatsProviderOption(provider),
+	)
+
+	// the second output is never read while values are written, so it only holds the first value
+	for i := 1; i <= 3; i++ {
+		inc <- i
+		require.Equal(t, i, <-outs[0])
+	}
+	close(inc)
+
+	var stats channels.BroadcastStats
+	for i := 0; i < 3; i++ {
+		stats = <-receiver.Channel()
+	}
+	require.Equal(t, channels.BroadcastOperator, stats.Operator)
+	require.Equal(t, []uint{0, 2}, stats.Dropped)
+
+	results := []int{}
+	for val := range outs[1] {
+		results = append(results, val)
+	}
+	require.Equal(t, []int{1}, results)
+}
+
+func TestBroadcastBufferOnFullOutput(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.BroadcastStats](10)
+	defer provider.Close()
+
+	inc := make(chan int)
+	outs := channels.Broadcast(inc, 2,
+		channels.BroadcastPoliciesOption([]channels.BroadcastPolicy{
+			{Mode: channels.BlockOnFullOutput},
+			{Mode: channels.BufferOnFullOutput, Limit: 2},
+		}),
+		channels.BroadcastStatsProviderOption(provider),
+	)
+
+	// the second output is never read while values are written, so values are buffered up to the limit
+	for i := 1; i <= 5; i++ {
+		inc <- i
+		require.Equal(t, i, <-outs[0])
+	}
+	close(inc)
+
+	var stats channels.BroadcastStats
+	for i := 0; i < 5; i++ {
+		stats = <-receiver.Channel()
+	}
+
+	results := []int{}
+	for val := range outs[1] {
+		results = append(results, val)
+	}
+
+	require.GreaterOrEqual(t, len(results), 2)
+	require.Equal(t, 5, len(results)+int(stats.Dropped[1]))
+	require.IsIncreasing(t, results)
+	require.Equal(t, 1, results[0])
+	require.Equal(t, uint(0), stats.Dropped[0])
+}
+
+func TestBroadcastContextOption(t *testing.T) {
+	t.Parallel()
+
+	inc := make(chan int)
+	defer close(inc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	outs := channels.Broadcast(inc, 2,
+		channels.BroadcastPoliciesOption([]channels.BroadcastPolicy{{Mode: channels.BufferOnFullOutput, Limit: 1}}),
+		channels.ContextOption[channels.BroadcastConfig](ctx),
+	)
+	cancel()
+
+	for _, out := range outs {
+		_, ok := <-out
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,197 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+// ErrCircuitOpen is the error reported in a Failure for values rejected by CircuitBreaker
+// while the circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+type CircuitState byte
+
+const (
+	// Values are passed to the breaker's function.
+	CircuitClosed CircuitState = iota
+	// Values are rejected without calling the breaker's function.
+	CircuitOpen
+	// The next value is passed to the breaker's function to decide whether to close
+	// or reopen the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitTransition describes a change in a circuit breaker's state.
+type CircuitTransition struct {
+	Operator OperatorKind
+	Name     string
+	From     CircuitState
+	To       CircuitState
+	Time     time.Time
+}
+
+// CircuitPolicy configures when a circuit breaker opens and closes.  The circuit
+// opens when Failures calls fail within the rolling Window duration.  After the
+// circuit has been open for the Cooldown duration, the next value is passed to the
+// breaker's function, closing the circuit if the call succeeds or reopening it if
+// the call fails.
+type CircuitPolicy struct {
+	Failures int
+	Window   time.Duration
+	Cooldown time.Duration
+}
+
+type CircuitBreakerConfig struct {
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	clock                clock.Clock
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	policy               CircuitPolicy
+	stateProvider        providers.Provider[CircuitTransition]
+}
+
+func defaultCircuitBreakerOptions() []Option[CircuitBreakerConfig] {
+	return []Option[CircuitBreakerConfig]{
+		CircuitPolicyOption(CircuitPolicy{Failures: 5, Window: 10 * time.Second, Cooldown: 30 * time.Second}),
+	}
+}
+
+// CircuitBreaker reads values from the input channel and calls `breakerFn` with each
+// value, writing the result to the output channel when `breakerFn` succeeds.  When too
+// many calls fail, as configured with CircuitPolicyOption, the circuit opens and values
+// are rejected without calling `breakerFn` until the circuit closes again.  Failed and
+// rejected values are sent to the provider configured with ErrorProviderOption, with
+// ErrCircuitOpen as the error for rejected values.  Changes to the circuit's state are
+// sent to the provider configured with CircuitStateProviderOption.  The output channel
+// is unbuffered by default, and will be closed when the input channel is closed and drained.
+func CircuitBreaker[TIn any, TOut any](inc <-chan TIn, breakerFn func(TIn) (TOut, error), opts ...Option[CircuitBreakerConfig]) <-chan TOut {
+	cfg := parseOpts(append(defaultCircuitBreakerOptions(), opts...)...)
+
+	outc := make(chan TOut, cfg.capacity)
+	operator := cfg.operator.or(CircuitBreakerOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	errorProvider := failureProvider[TIn](cfg.errorProvider)
+	stateProvider := cfg.stateProvider
+	policy := cfg.policy
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	state := CircuitClosed
+	var openedAt time.Time
+	failures := []time.Time{}
+
+	transition := func(to CircuitState, now time.Time) {
+		if stateProvider != nil {
+			stateProvider.Provide(CircuitTransition{Operator: operator, Name: name, From: state, To: to, Time: now})
+		}
+
+		state = to
+		switch to {
+		case CircuitOpen:
+			openedAt = now
+		case CircuitClosed:
+			failures = failures[:0]
+		}
+	}
+
+	// recordFailure tracks a failed call, opening the circuit when the policy's threshold is reached
+	recordFailure := func(now time.Time) {
+		if state == CircuitHalfOpen {
+			transition(CircuitOpen, now)
+			return
+		}
+
+		failures = append(failures, now)
+		expired := 0
+		for expired < len(failures) && now.Sub(failures[expired]) >= policy.Window {
+			expired++
+		}
+		failures = failures[expired:]
+
+		if len(failures) >= policy.Failures {
+			transition(CircuitOpen, now)
+		}
+	}
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok {
+				return
+			}
+
+			now := clk.Now()
+			if state == CircuitOpen {
+				if now.Sub(openedAt) < policy.Cooldown {
+					tryProvideFailure(Failure[TIn]{Operator: operator, Name: name, Input: in, Err: ErrCircuitOpen, Time: now}, errorProvider)
+					continue
+				}
+
+				transition(CircuitHalfOpen, now)
+			}
+
+			var out TOut
+			var err error
+
+			start := clk.Now()
+			recovered := !panics.tryRecover(in, func() { out, err = breakerFn(in) })
+			tryProvideStats(Stats{Operator: operator, Name: name, Duration: clk.Now().Sub(start), QueueLength: len(inc)}, statsProvider)
+
+			now = clk.Now()
+			if recovered || err != nil {
+				if err != nil {
+					tryProvideFailure(Failure[TIn]{Operator: operator, Name: name, Input: in, Err: err, Time: now}, errorProvider)
+				}
+				recordFailure(now)
+				continue
+			}
+
+			if state == CircuitHalfOpen {
+				transition(CircuitClosed, now)
+			}
+
+			if !send(ctx, outc, out) {
+				return
+			}
+		}
+	}()
+
+	return outc
+}
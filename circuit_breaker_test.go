@@ -0,0 +1,145 @@
+package channels_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+
+	errorProvider, errorReceiver := providers.NewProvider[channels.Failure[int]](10)
+	defer errorProvider.Close()
+
+	stateProvider, stateReceiver := providers.NewProvider[channels.CircuitTransition](10)
+	defer stateProvider.Close()
+
+	failed := errors.New("failed")
+	out := channels.CircuitBreaker(in,
+		func(i int) (int, error) {
+			if i < 0 {
+				return 0, failed
+			}
+			return i, nil
+		},
+		channels.ClockOption[channels.CircuitBreakerConfig](clk),
+		channels.ErrorProviderOption[channels.CircuitBreakerConfig](errorProvider),
+		channels.CircuitStateProviderOption(stateProvider),
+		channels.CircuitPolicyOption(channels.CircuitPolicy{Failures: 2, Window: time.Second, Cooldown: time.Minute}),
+		channels.ChannelCapacityOption[channels.CircuitBreakerConfig](10),
+	)
+
+	// failures outside of the rolling window don't open the circuit
+	in <- -1
+	require.ErrorIs(t, (<-errorReceiver.Channel()).Err, failed)
+	clk.Advance(time.Second)
+	in <- -2
+	require.ErrorIs(t, (<-errorReceiver.Channel()).Err, failed)
+	in <- 1
+	require.Equal(t, 1, <-out)
+	require.Len(t, stateReceiver.Channel(), 0)
+
+	// failures within the rolling window open the circuit
+	in <- -3
+	transition := <-stateReceiver.Channel()
+	require.Equal(t, channels.CircuitBreakerOperator, transition.Operator)
+	require.Equal(t, channels.CircuitClosed, transition.From)
+	require.Equal(t, channels.CircuitOpen, transition.To)
+	require.ErrorIs(t, (<-errorReceiver.Channel()).Err, failed)
+
+	// values are rejected while the circuit is open
+	in <- 2
+	failure := <-errorReceiver.Channel()
+	require.Equal(t, 2, failure.Input)
+	require.ErrorIs(t, failure.Err, channels.ErrCircuitOpen)
+
+	// a failure while half-open reopens the circuit
+	clk.Advance(time.Minute)
+	in <- -4
+	require.Equal(t, channels.CircuitHalfOpen, (<-stateReceiver.Channel()).To)
+	require.Equal(t, channels.CircuitOpen, (<-stateReceiver.Channel()).To)
+	require.ErrorIs(t, (<-errorReceiver.Channel()).Err, failed)
+
+	// a success while half-open closes the circuit
+	clk.Advance(time.Minute)
+	in <- 3
+	require.Equal(t, 3, <-out)
+	require.Equal(t, channels.CircuitHalfOpen, (<-stateReceiver.Channel()).To)
+	require.Equal(t, channels.CircuitClosed, (<-stateReceiver.Channel()).To)
+
+	in <- 4
+	require.Equal(t, 4, <-out)
+}
+
+func TestCircuitBreakerPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	stateProvider, stateReceiver := providers.NewProvider[channels.CircuitTransition](10)
+	defer stateProvider.Close()
+
+	panicProvider, panicReceiver := providers.NewProvider[any](10)
+	defer panicProvider.Close()
+
+	channels.CircuitBreaker(in,
+		func(i int) (int, error) { panic("panic!") },
+		channels.PanicProviderOption[channels.CircuitBreakerConfig](panicProvider),
+		channels.PanicRecoveryOption[channels.CircuitBreakerConfig](channels.ContinueOnPanic),
+		channels.CircuitStateProviderOption(stateProvider),
+		channels.CircuitPolicyOption(channels.CircuitPolicy{Failures: 1, Window: time.Minute, Cooldown: time.Minute}),
+	)
+
+	// recovered panics count as failures
+	in <- 1
+	require.Equal(t, channels.CircuitOpen, (<-stateReceiver.Channel()).To)
+	require.Equal(t, 1, (<-panicReceiver.Channel()).(channels.RecoveredPanic).Input)
+}
+
+func TestCircuitStateString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "closed", channels.CircuitClosed.String())
+	require.Equal(t, "open", channels.CircuitOpen.String())
+	require.Equal(t, "half-open", channels.CircuitHalfOpen.String())
+}
+
+func TestCircuitBreakerContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.CircuitBreaker(in,
+		func(i int) (int, error) { return i, nil },
+		channels.ContextOption[channels.CircuitBreakerConfig](ctx),
+		channels.CancellationProviderOption[channels.CircuitBreakerConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
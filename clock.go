@@ -0,0 +1,11 @@
+package channels
+
+import "github.com/jonabc/channels/clock"
+
+func clockOrReal(c clock.Clock) clock.Clock {
+	if c == nil {
+		return clock.Real()
+	}
+
+	return c
+}
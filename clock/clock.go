@@ -0,0 +1,100 @@
+// Package clock provides the time functions used by time based channels
+// functions, along with a manually advanced clock for deterministic tests.
+package clock
+
+import "time"
+
+// Clocks provide the current time, timers and tickers
+type Clock interface {
+	// Returns the current time
+	Now() time.Time
+
+	// Returns a timer that sends the current time on its channel after `d` duration
+	NewTimer(d time.Duration) Timer
+
+	// Returns a ticker that sends the current time on its channel every `d` duration
+	NewTicker(d time.Duration) Ticker
+
+	// Returns a timer that calls `f` in its own goroutine after `d` duration.
+	// The returned timer's channel is nil.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timers send a single value on their channel after a duration
+type Timer interface {
+	// Returns the channel the timer sends on when it fires
+	C() <-chan time.Time
+
+	// Reset the timer to fire after `d` duration.  Returns true if the timer was active
+	Reset(d time.Duration) bool
+
+	// Stop the timer.  Returns true if the timer was active
+	Stop() bool
+}
+
+// Tickers send values on their channel on a recurring interval
+type Ticker interface {
+	// Returns the channel the ticker sends on with each tick
+	C() <-chan time.Time
+
+	// Reset the ticker's interval to `d` duration
+	Reset(d time.Duration)
+
+	// Stop the ticker
+	Stop()
+}
+
+// Real returns a clock backed by the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{internal: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{internal: time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &realTimer{internal: time.AfterFunc(d, f)}
+}
+
+type realTimer struct {
+	internal *time.Timer
+}
+
+func (t *realTimer) C() <-chan time.Time {
+	return t.internal.C
+}
+
+func (t *realTimer) Reset(d time.Duration) bool {
+	return t.internal.Reset(d)
+}
+
+func (t *realTimer) Stop() bool {
+	return t.internal.Stop()
+}
+
+type realTicker struct {
+	internal *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time {
+	return t.internal.C
+}
+
+func (t *realTicker) Reset(d time.Duration) {
+	t.internal.Reset(d)
+}
+
+func (t *realTicker) Stop() {
+	t.internal.Stop()
+}
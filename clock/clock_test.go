@@ -0,0 +1,120 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels/clock"
+)
+
+func TestRealClock(t *testing.T) {
+	t.Parallel()
+
+	c := clock.Real()
+	require.WithinDuration(t, time.Now(), c.Now(), time.Second)
+
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+	require.False(t, timer.Stop())
+
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	<-ticker.C()
+	ticker.Stop()
+
+	called := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() { close(called) })
+	<-called
+}
+
+func TestManualClockTimer(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewManual(start)
+	require.Equal(t, start, c.Now())
+
+	timer := c.NewTimer(10 * time.Second)
+	require.Equal(t, 1, c.Waiters())
+
+	c.Advance(9 * time.Second)
+	require.Len(t, timer.C(), 0)
+
+	c.Advance(1 * time.Second)
+	require.Equal(t, start.Add(10*time.Second), <-timer.C())
+	require.Equal(t, 0, c.Waiters())
+	require.False(t, timer.Stop())
+
+	require.False(t, timer.Reset(5*time.Second))
+	require.True(t, timer.Stop())
+	c.Advance(time.Minute)
+	require.Len(t, timer.C(), 0)
+}
+
+func TestManualClockTicker(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewManual(start)
+
+	ticker := c.NewTicker(time.Second)
+
+	c.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), <-ticker.C())
+
+	// ticks are dropped while the previous tick hasn't been read
+	c.Advance(3 * time.Second)
+	require.Equal(t, start.Add(2*time.Second), <-ticker.C())
+	require.Len(t, ticker.C(), 0)
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Minute)
+	require.Equal(t, start.Add(4*time.Second+time.Minute), <-ticker.C())
+
+	ticker.Stop()
+	require.Equal(t, 0, c.Waiters())
+}
+
+func TestManualClockAfterFunc(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewManual(time.Now())
+
+	called := make(chan time.Time, 1)
+	timer := c.AfterFunc(time.Second, func() { called <- c.Now() })
+	require.Nil(t, timer.C())
+
+	c.Advance(time.Second)
+	<-called
+}
+
+func TestManualClockFiresInDeadlineOrder(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewManual(start)
+
+	late := c.NewTimer(2 * time.Second)
+	early := c.NewTimer(1 * time.Second)
+
+	c.Set(start.Add(time.Minute))
+	require.Equal(t, start.Add(time.Second), <-early.C())
+	require.Equal(t, start.Add(2*time.Second), <-late.C())
+	require.Equal(t, start.Add(time.Minute), c.Now())
+}
+
+func TestManualClockBlockUntil(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewManual(time.Now())
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		c.NewTimer(time.Second)
+	}()
+
+	c.BlockUntil(1)
+	require.Equal(t, 1, c.Waiters())
+}
@@ -0,0 +1,230 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Manual is a clock that only moves when it is advanced.  Timers, tickers and
+// functions scheduled with AfterFunc fire as the clock is advanced past their
+// deadlines, in deadline order.
+type Manual struct {
+	now     time.Time
+	waiters []*manualWaiter
+	mu      sync.Mutex
+	cond    *sync.Cond
+}
+
+type manualWaiter struct {
+	clock    *Manual
+	deadline time.Time
+	period   time.Duration
+	c        chan time.Time
+	fn       func()
+	active   bool
+}
+
+// NewManual returns a manual clock set to `now`.
+func NewManual(now time.Time) *Manual {
+	clock := &Manual{now: now}
+	clock.cond = sync.NewCond(&clock.mu)
+	return clock
+}
+
+// Returns the clock's current time
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.now
+}
+
+// Returns a timer that fires once the clock is advanced by `d` duration
+func (m *Manual) NewTimer(d time.Duration) Timer {
+	return m.schedule(d, 0, nil)
+}
+
+// Returns a ticker that fires each time the clock is advanced by `d` duration
+func (m *Manual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	return &manualTicker{waiter: m.schedule(d, d, nil)}
+}
+
+// Returns a timer that calls `f` in its own goroutine once the clock is advanced
+// by `d` duration
+func (m *Manual) AfterFunc(d time.Duration, f func()) Timer {
+	return m.schedule(d, 0, f)
+}
+
+// Advance moves the clock forward by `d` duration, firing any timers and tickers
+// whose deadlines are passed.
+func (m *Manual) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setLocked(m.now.Add(d))
+}
+
+// Set moves the clock to `now`, firing any timers and tickers whose deadlines are passed.
+func (m *Manual) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setLocked(now)
+}
+
+// Returns the number of active timers and tickers waiting on the clock
+func (m *Manual) Waiters() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.waiters)
+}
+
+// BlockUntil blocks until at least `n` timers and tickers are waiting on the clock.
+// Tests can use BlockUntil to wait for a goroutine to start waiting on the clock
+// before advancing it.
+func (m *Manual) BlockUntil(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for len(m.waiters) < n {
+		m.cond.Wait()
+	}
+}
+
+func (m *Manual) schedule(d time.Duration, period time.Duration, fn func()) *manualWaiter {
+	waiter := &manualWaiter{clock: m, period: period, fn: fn}
+	if fn == nil {
+		waiter.c = make(chan time.Time, 1)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resetLocked(waiter, d)
+	return waiter
+}
+
+func (m *Manual) resetLocked(waiter *manualWaiter, d time.Duration) bool {
+	wasActive := m.stopLocked(waiter)
+
+	waiter.deadline = m.now.Add(d)
+	if d <= 0 {
+		m.fireLocked(waiter)
+		return wasActive
+	}
+
+	waiter.active = true
+	m.waiters = append(m.waiters, waiter)
+	m.cond.Broadcast()
+	return wasActive
+}
+
+func (m *Manual) stopLocked(waiter *manualWaiter) bool {
+	if waiter.c != nil {
+		// drain a stale value so the channel doesn't fire after being stopped or reset
+		select {
+		case <-waiter.c:
+		default:
+		}
+	}
+
+	if !waiter.active {
+		return false
+	}
+
+	waiter.active = false
+	for i, w := range m.waiters {
+		if w == waiter {
+			m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (m *Manual) setLocked(now time.Time) {
+	for {
+		sort.SliceStable(m.waiters, func(i, j int) bool {
+			return m.waiters[i].deadline.Before(m.waiters[j].deadline)
+		})
+
+		if len(m.waiters) == 0 || m.waiters[0].deadline.After(now) {
+			break
+		}
+
+		waiter := m.waiters[0]
+		m.now = waiter.deadline
+		if waiter.period > 0 {
+			waiter.deadline = waiter.deadline.Add(waiter.period)
+		} else {
+			waiter.active = false
+			m.waiters = m.waiters[1:]
+		}
+
+		m.fireLocked(waiter)
+	}
+
+	m.now = now
+}
+
+func (m *Manual) fireLocked(waiter *manualWaiter) {
+	if waiter.fn != nil {
+		go waiter.fn()
+		return
+	}
+
+	select {
+	case waiter.c <- m.now:
+	default:
+		// drop the tick if the previous value hasn't been read, like time.Ticker
+	}
+}
+
+func (w *manualWaiter) C() <-chan time.Time {
+	return w.c
+}
+
+func (w *manualWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	return w.clock.resetLocked(w, d)
+}
+
+func (w *manualWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	return w.clock.stopLocked(w)
+}
+
+type manualTicker struct {
+	waiter *manualWaiter
+}
+
+func (t *manualTicker) C() <-chan time.Time {
+	return t.waiter.c
+}
+
+func (t *manualTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+
+	t.waiter.clock.mu.Lock()
+	defer t.waiter.clock.mu.Unlock()
+
+	t.waiter.period = d
+	t.waiter.clock.resetLocked(t.waiter, d)
+}
+
+func (t *manualTicker) Stop() {
+	t.waiter.Stop()
+}
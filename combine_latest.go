@@ -0,0 +1,96 @@
+package channels
+
+import (
+	"context"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+type CombineConfig struct {
+	panicProvider        providers.Provider[any]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
+}
+
+// Pair holds values read from two input channels by Zip, CombineLatest and WithLatestFrom.
+type Pair[T1 any, T2 any] struct {
+	First  T1
+	Second T2
+}
+
+// CombineLatest reads values from both input channels, and each time a value is read
+// from either input channel writes a Pair of the latest values read from each input
+// channel to the output channel.  Nothing is written until a value has been read from
+// both input channels.  When one input channel is closed, its latest value continues
+// to be paired with new values from the other input channel.  The output channel is
+// unbuffered by default, and will be closed when both input channels are closed, or
+// when either input channel is closed before a value is read from it.
+func CombineLatest[T1 any, T2 any](inc1 <-chan T1, inc2 <-chan T2, opts ...Option[CombineConfig]) <-chan Pair[T1, T2] {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan Pair[T1, T2], cfg.capacity)
+	operator := cfg.operator.or(CombineLatestOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+
+	drainOnStop(lifecycle, inc1)
+	drainOnStop(lifecycle, inc2)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		var latest Pair[T1, T2]
+		var hasFirst, hasSecond bool
+
+		for inc1 != nil || inc2 != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case in, ok := <-inc1:
+				if !ok {
+					if !hasFirst {
+						return
+					}
+					inc1 = nil
+					continue
+				}
+				latest.First = in
+				hasFirst = true
+			case in, ok := <-inc2:
+				if !ok {
+					if !hasSecond {
+						return
+					}
+					inc2 = nil
+					continue
+				}
+				latest.Second = in
+				hasSecond = true
+			}
+
+			if hasFirst && hasSecond && !send(ctx, outc, latest) {
+				return
+			}
+		}
+	}()
+
+	return outc
+}
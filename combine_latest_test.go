@@ -0,0 +1,69 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonabc/channels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombineLatest(t *testing.T) {
+	t.Parallel()
+
+	numbers := make(chan int)
+	letters := make(chan string)
+
+	out := channels.CombineLatest(numbers, letters)
+	require.Equal(t, 0, cap(out))
+
+	// nothing is written until both inputs have a value
+	numbers <- 1
+	numbers <- 2
+	letters <- "a"
+	require.Equal(t, channels.Pair[int, string]{First: 2, Second: "a"}, <-out)
+
+	letters <- "b"
+	require.Equal(t, channels.Pair[int, string]{First: 2, Second: "b"}, <-out)
+
+	// the latest value from a closed input continues to be paired
+	close(letters)
+	numbers <- 3
+	require.Equal(t, channels.Pair[int, string]{First: 3, Second: "b"}, <-out)
+
+	close(numbers)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestCombineLatestClosesWhenInputClosesWithoutValues(t *testing.T) {
+	t.Parallel()
+
+	numbers := make(chan int)
+	letters := make(chan string)
+	defer close(numbers)
+
+	out := channels.CombineLatest(numbers, letters)
+
+	numbers <- 1
+	close(letters)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestCombineLatestContextOption(t *testing.T) {
+	t.Parallel()
+
+	inc1 := make(chan int)
+	inc2 := make(chan int)
+	defer close(inc1)
+	defer close(inc2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.CombineLatest(inc1, inc2, channels.ContextOption[channels.CombineConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
@@ -0,0 +1,164 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+)
+
+type OutputOrder byte
+
+const (
+	// Results are written to the output channel as soon as they are ready.
+	UnorderedOutput OutputOrder = iota
+	// Results are written to the output channel in the order that values
+	// were read from the input channel.
+	OrderedOutput
+)
+
+type workResult[TIn any, TOut any] struct {
+	in       TIn
+	out      TOut
+	ok       bool
+	duration time.Duration
+	busy     int
+}
+
+type orderedWork[TIn any, TOut any] struct {
+	in      TIn
+	resultc chan workResult[TIn, TOut]
+}
+
+// processConcurrently reads values from the input channel and calls `workFn` with each
+// value from up to `workers` goroutines, passing each result to `emitFn`.  processConcurrently
+// blocks until the input channel is closed, the context is done, or `emitFn` returns false.
+// Unless the panic is recovered with ContinueOnPanic, if a worker panics the remaining
+// workers are stopped and the panic is handled by the worker's goroutine.
+func processConcurrently[TIn any, TOut any](
+	ctx context.Context,
+	inc <-chan TIn,
+	workers int,
+	order OutputOrder,
+	clk clock.Clock,
+	panics *panicHandler,
+	workFn func(TIn) (TOut, bool),
+	emitFn func(context.Context, workResult[TIn, TOut]) bool,
+) {
+	var busy atomic.Int32
+	process := func(in TIn) workResult[TIn, TOut] {
+		count := int(busy.Add(1))
+		defer busy.Add(-1)
+
+		start := clk.Now()
+		var out TOut
+		var ok bool
+		panics.tryRecover(in, func() { out, ok = workFn(in) })
+		return workResult[TIn, TOut]{in: in, out: out, ok: ok, duration: clk.Now().Sub(start), busy: count}
+	}
+
+	if workers <= 1 {
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok || !emitFn(ctx, process(in)) {
+				return
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	startWorker := func(work func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer panics.handle()
+
+			// stop the remaining workers if this worker exits from a panic
+			completed := false
+			defer func() {
+				if !completed {
+					cancel()
+				}
+			}()
+
+			work()
+			completed = true
+		}()
+	}
+
+	if order == UnorderedOutput {
+		for i := 0; i < workers; i++ {
+			startWorker(func() {
+				for {
+					in, ok := receive(ctx, inc)
+					if !ok {
+						return
+					}
+
+					if !emitFn(ctx, process(in)) {
+						cancel()
+						return
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+		return
+	}
+
+	work := make(chan orderedWork[TIn, TOut])
+	results := make(chan chan workResult[TIn, TOut], workers)
+
+	for i := 0; i < workers; i++ {
+		startWorker(func() {
+			for {
+				next, ok := receive(ctx, work)
+				if !ok {
+					return
+				}
+
+				func() {
+					// closing the result channel without a result signals a panic to the emitter
+					defer close(next.resultc)
+					next.resultc <- process(next.in)
+				}()
+			}
+		})
+	}
+
+	emitted := make(chan struct{})
+	go func() {
+		defer close(emitted)
+
+		for resultc := range results {
+			result, ok := receive(ctx, resultc)
+			if !ok || !emitFn(ctx, result) {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	for {
+		in, ok := receive(ctx, inc)
+		if !ok {
+			break
+		}
+
+		resultc := make(chan workResult[TIn, TOut], 1)
+		if !send(ctx, results, resultc) || !send(ctx, work, orderedWork[TIn, TOut]{in: in, resultc: resultc}) {
+			break
+		}
+	}
+
+	close(work)
+	close(results)
+	wg.Wait()
+	<-emitted
+}
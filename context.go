@@ -0,0 +1,71 @@
+package channels
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return ctx
+}
+
+// receive reads the next value from the input channel.  The returned bool is
+// false when the input channel is closed or the context is done.
+func receive[T any](ctx context.Context, inc <-chan T) (T, bool) {
+	// Give preference to the context to stop reading as soon as it's done
+	select {
+	case <-ctx.Done():
+		return *new(T), false
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return *new(T), false
+	case in, ok := <-inc:
+		return in, ok
+	}
+}
+
+// send writes a value to the output channel, returning false if the context
+// is done before the value could be written.
+func send[T any](ctx context.Context, outc chan<- T, val T) bool {
+	// Give preference to the context to avoid writing after it's done
+	select {
+	case <-ctx.Done():
+		return false
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case outc <- val:
+		return true
+	}
+}
+
+// timedSend is like send, and also returns the time spent blocked on the write
+// measured with `clk`.
+func timedSend[T any](ctx context.Context, clk clock.Clock, outc chan<- T, val T) (time.Duration, bool) {
+	start := clk.Now()
+	ok := send(ctx, outc, val)
+	return clk.Now().Sub(start), ok
+}
+
+func tryProvideCancellation(ctx context.Context, provider providers.Provider[error]) {
+	if provider == nil {
+		return
+	}
+
+	if err := context.Cause(ctx); err != nil {
+		provider.Provide(err)
+	}
+}
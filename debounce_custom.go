@@ -1,9 +1,11 @@
 package channels
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
@@ -17,10 +19,19 @@ const (
 
 // DebounceConfig contains user configurable options for the Debounce functions
 type DebounceConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[DebounceStats]
-	capacity      int
-	debounceType  DebounceType
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[DebounceStats]
+	capacity             int
+	debounceType         DebounceType
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	clock                clock.Clock
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
 }
 
 func defaultDebounceOptions() []Option[DebounceConfig] {
@@ -49,9 +60,15 @@ func DebounceCustom[K comparable, T DebounceInput[K, T]](inc <-chan T, opts ...O
 
 	outc := make(chan T, cfg.capacity)
 	done := make(chan struct{})
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(DebounceCustomOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
 	debounceType := cfg.debounceType
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
 	// the buffer stores a map of key value pairs of
 	// items from the input channel currently being debounced
@@ -59,41 +76,66 @@ func DebounceCustom[K comparable, T DebounceInput[K, T]](inc <-chan T, opts ...O
 		data: make(map[K]*debounceItem[K, T]),
 	}
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
 		var wg sync.WaitGroup
-		for next := range inc {
-			key := next.Key()
-			if buffer.add(key, next) {
+		for {
+			next, ok := receive(ctx, inc)
+			if !ok {
+				break
+			}
+
+			var key K
+			var added bool
+			if !panics.tryRecover(next, func() {
+				key = next.Key()
+				added = buffer.add(key, next)
+			}) {
+				continue
+			}
+
+			if added {
+				var delay time.Duration
+				if !panics.tryRecover(next, func() { delay = next.Delay() }) {
+					// stop debouncing the key when a delay isn't available
+					buffer.remove(key)
+					continue
+				}
+
 				wg.Add(1)
 
 				go func(key K, delay time.Duration) {
-					defer tryHandlePanic(panicProvider)
 					defer wg.Done()
+					defer panics.handle()
 
-					start := time.Now()
+					start := clk.Now()
 
-					timer := time.NewTimer(delay)
+					timer := clk.NewTimer(delay)
 					select {
 					case <-done:
 						timer.Stop()
-					case <-timer.C:
+					case <-timer.C():
 					}
 
-					duration := time.Since(start)
+					duration := clk.Now().Sub(start)
 					item, count := buffer.remove(key)
 
-					if debounceType&TailDebounceType == TailDebounceType {
-						outc <- item
-						tryProvideStats(DebounceStats{Delay: duration, Count: count}, statsProvider)
+					if debounceType&TailDebounceType == TailDebounceType && send(ctx, outc, item) {
+						tryProvideStats(DebounceStats{Operator: operator, Name: name, Delay: duration, Count: count}, statsProvider)
 					}
-				}(key, next.Delay())
+				}(key, delay)
 
 				if debounceType&LeadDebounceType == LeadDebounceType {
-					outc <- next
-					tryProvideStats(DebounceStats{Delay: 0, Count: 1}, statsProvider)
+					if !send(ctx, outc, next) {
+						break
+					}
+					tryProvideStats(DebounceStats{Operator: operator, Name: name, Delay: 0, Count: 1}, statsProvider)
 				}
 			}
 		}
@@ -1,11 +1,13 @@
 package channels_test
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
@@ -158,3 +160,111 @@ func TestDebounceCustomLeadTailDebounceTypeOption(t *testing.T) {
 	require.GreaterOrEqual(t, time.Since(start), delay)
 	require.Equal(t, 0, getDebouncedCount())
 }
+
+func TestDebounceCustomContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *customDebouncingType, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out, _ := channels.DebounceCustom(in,
+		channels.ContextOption[channels.DebounceConfig](ctx),
+		channels.CancellationProviderOption[channels.DebounceConfig](provider),
+	)
+
+	in <- &customDebouncingType{key: "1", value: "val1", delay: time.Hour}
+	time.Sleep(1 * time.Millisecond)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestDebounceCustomClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *customDebouncingType, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	provider, receiver := providers.NewProvider[channels.DebounceStats](1)
+	defer provider.Close()
+
+	out, _ := channels.DebounceCustom(in,
+		channels.ClockOption[channels.DebounceConfig](clk),
+		channels.DebounceStatsProviderOption(provider),
+	)
+
+	in <- &customDebouncingType{key: "1", value: "val1", delay: time.Hour}
+	in <- &customDebouncingType{key: "1", value: "val2", delay: time.Minute}
+	clk.BlockUntil(1)
+
+	clk.Advance(time.Minute)
+	require.Len(t, out, 0)
+
+	clk.Advance(time.Hour - time.Minute)
+	require.Equal(t, &customDebouncingType{key: "1", value: "val1,val2", delay: time.Hour}, <-out)
+
+	stats := <-receiver.Channel()
+	require.Equal(t, time.Hour, stats.Delay)
+	require.Equal(t, uint(2), stats.Count)
+}
+
+type panickingDebouncingType struct {
+	customDebouncingType
+	panicOnDelay bool
+}
+
+func (d *panickingDebouncingType) Delay() time.Duration {
+	if d.panicOnDelay {
+		panic("delay")
+	}
+	return d.delay
+}
+
+func (d *panickingDebouncingType) Reduce(other *panickingDebouncingType) (*panickingDebouncingType, bool) {
+	if other.value == "panic" {
+		panic("reduce")
+	}
+
+	d.value += "," + other.value
+	return d, true
+}
+
+func TestDebounceCustomPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *panickingDebouncingType, 100)
+
+	provider, receiver := providers.NewCollectingProvider[any](0)
+	defer provider.Close()
+
+	out, getDebouncedCount := channels.DebounceCustom(in,
+		channels.PanicProviderOption[channels.DebounceConfig](provider),
+		channels.PanicRecoveryOption[channels.DebounceConfig](channels.ContinueOnPanic),
+	)
+
+	in <- &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "1", value: "val1"}, panicOnDelay: true}
+	in <- &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "2", value: "val1", delay: time.Hour}}
+	in <- &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "2", value: "panic"}}
+	in <- &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "2", value: "val2"}}
+	time.Sleep(time.Millisecond)
+
+	// the key that panicked while reading its delay isn't debounced
+	require.Equal(t, 1, getDebouncedCount())
+	close(in)
+
+	result := <-out
+	require.Equal(t, "2", result.key)
+	require.Equal(t, "val1,val2", result.value)
+
+	panics := <-receiver.Channel()
+	require.Len(t, panics, 2)
+	require.Equal(t, "delay", panics[0].(channels.RecoveredPanic).Value)
+	require.Equal(t, "reduce", panics[1].(channels.RecoveredPanic).Value)
+}
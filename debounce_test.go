@@ -1,6 +1,7 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -85,6 +86,7 @@ func TestDebounceProviderOptionWithStatsReporting(t *testing.T) {
 	require.Len(t, stats, 1)
 	require.GreaterOrEqual(t, stats[0].Delay, 5*time.Millisecond)
 	require.Equal(t, uint(2), stats[0].Count)
+	require.Equal(t, channels.DebounceOperator, stats[0].Operator)
 }
 
 func TestDebounceLeadDebounceTypeOption(t *testing.T) {
@@ -143,3 +145,21 @@ func TestDebounceLeadTailDebounceTypeOption(t *testing.T) {
 	require.GreaterOrEqual(t, time.Since(start), delay)
 	require.Equal(t, 0, getDebouncedCount())
 }
+
+func TestDebounceContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, _ := channels.Debounce(in, time.Hour,
+		channels.ContextOption[channels.DebounceConfig](ctx),
+	)
+
+	in <- 1
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
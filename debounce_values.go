@@ -27,22 +27,36 @@ func DebounceValues[T comparable](inc <-chan T, delay time.Duration, opts ...Opt
 	cfg := parseOpts(opts...)
 
 	outc := make(chan T, cfg.capacity)
+	// the bridging goroutines are stopped by the stop handle along with the wrapped function
+	ctx, release := cfg.stopHandle.bind(contextOrBackground(cfg.ctx))
+	inDone := make(chan struct{})
 
 	inBridge := make(chan *debounceValuesInput[T])
 	go func() {
+		defer close(inDone)
+		defer drainStopped(cfg.stopHandle, inc)
 		defer close(inBridge)
-		for in := range inc {
-			inBridge <- &debounceValuesInput[T]{val: in, delay: delay}
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok || !send(ctx, inBridge, &debounceValuesInput[T]{val: in, delay: delay}) {
+				return
+			}
 		}
 	}()
 
 	outBridge, getDebouncedCount := DebounceCustom(inBridge,
-		append(opts, ChannelCapacityOption[DebounceConfig](0))...,
+		append(withOperator(DebounceValuesOperator, opts), ChannelCapacityOption[DebounceConfig](0))...,
 	)
 	go func() {
+		defer func() {
+			<-inDone
+			release()
+		}()
 		defer close(outc)
 		for out := range outBridge {
-			outc <- out.val
+			if !send(ctx, outc, out.val) {
+				return
+			}
 		}
 	}()
 
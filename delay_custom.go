@@ -1,18 +1,29 @@
 package channels
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 // DelayConfig contains user configurable options for the Delay functions
 type DelayConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[Stats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	clock                clock.Clock
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
 }
 
 type Delayable interface {
@@ -27,35 +38,56 @@ func DelayCustom[T Delayable](inc <-chan T, opts ...Option[DelayConfig]) (<-chan
 
 	outc := make(chan T, cfg.capacity)
 	done := make(chan struct{})
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(DelayCustomOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
 	var count atomic.Int32
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
 		var wg sync.WaitGroup
-		for next := range inc {
+		for {
+			next, ok := receive(ctx, inc)
+			if !ok {
+				break
+			}
+
 			wg.Add(1)
 			count.Add(1)
 			go func(item T) {
-				defer tryHandlePanic(panicProvider)
 				defer count.Add(-1)
 				defer wg.Done()
+				defer panics.handle()
+
+				var delay time.Duration
+				if !panics.tryRecover(item, func() { delay = item.Delay() }) {
+					return
+				}
 
-				delay := item.Delay()
 				if delay > 0 {
-					timer := time.NewTimer(delay)
+					timer := clk.NewTimer(delay)
 					select {
 					case <-done:
 						timer.Stop()
-					case <-timer.C:
+					case <-timer.C():
 					}
 				}
 
-				outc <- item
-				tryProvideStats(Stats{Duration: delay, QueueLength: len(inc)}, statsProvider)
+				if send(ctx, outc, item) {
+					tryProvideStats(Stats{Operator: operator, Name: name, Duration: delay, QueueLength: len(inc)}, statsProvider)
+				}
 			}(next)
 		}
 
@@ -1,10 +1,12 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
@@ -50,3 +52,74 @@ func TestDelayCustomAcceptsOptions(t *testing.T) {
 
 	require.Equal(t, 5, cap(out))
 }
+
+func TestDelayCustomContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *customDebouncingType, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out, _ := channels.DelayCustom(in,
+		channels.ContextOption[channels.DelayConfig](ctx),
+		channels.CancellationProviderOption[channels.DelayConfig](provider),
+	)
+
+	in <- &customDebouncingType{key: "1", value: "val1", delay: time.Hour}
+	time.Sleep(1 * time.Millisecond)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestDelayCustomClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *customDebouncingType, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	out, getDelayedCount := channels.DelayCustom(in,
+		channels.ClockOption[channels.DelayConfig](clk),
+	)
+
+	input := &customDebouncingType{key: "1", value: "val1", delay: time.Hour}
+	in <- input
+	clk.BlockUntil(1)
+	require.Equal(t, 1, getDelayedCount())
+
+	clk.Advance(time.Hour)
+	require.Equal(t, input, <-out)
+}
+
+func TestDelayCustomPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *panickingDebouncingType, 100)
+
+	provider, receiver := providers.NewProvider[any](1)
+	defer provider.Close()
+
+	out, _ := channels.DelayCustom(in,
+		channels.PanicProviderOption[channels.DelayConfig](provider),
+		channels.PanicRecoveryOption[channels.DelayConfig](channels.ContinueOnPanic),
+	)
+
+	panicking := &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "1"}, panicOnDelay: true}
+	in <- panicking
+	in <- &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "2"}}
+	close(in)
+
+	require.Equal(t, "2", (<-out).key)
+	_, ok := <-out
+	require.False(t, ok)
+
+	recovered := (<-receiver.Channel()).(channels.RecoveredPanic)
+	require.Equal(t, "delay", recovered.Value)
+	require.Equal(t, panicking, recovered.Input)
+}
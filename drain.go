@@ -1,17 +1,30 @@
 package channels
 
 import (
+	"context"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	internalTime "github.com/jonabc/channels/internal/time"
+	"github.com/jonabc/channels/providers"
 )
 
+// DrainConfig contains user configurable options for the Drain functions
+type DrainConfig struct {
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	clock                clock.Clock
+}
+
 // Drain blocks until either the input channel is fully drained and closed or `maxWait` duration has passed.
 // Drain returns the count of values drained from the channel, and a bool that is true when exiting due to
 // the input channel being drained and closed or false when exiting due to waiting for the `maxWait` duration.
 // When `maxWait <= 0`, Drain will wait forever, and only exit when the input channel is closed.
-func Drain[T any](inc <-chan T, maxWait time.Duration) (int, bool) {
-	ticker := internalTime.NewTicker(maxWait)
+func Drain[T any](inc <-chan T, maxWait time.Duration, opts ...Option[DrainConfig]) (int, bool) {
+	cfg := parseOpts(opts...)
+
+	ctx := contextOrBackground(cfg.ctx)
+	ticker := internalTime.NewTicker(clockOrReal(cfg.clock), maxWait)
 	defer ticker.Stop()
 
 	count := 0
@@ -25,6 +38,9 @@ func Drain[T any](inc <-chan T, maxWait time.Duration) (int, bool) {
 			count++
 		case <-ticker.C:
 			return count, false
+		case <-ctx.Done():
+			tryProvideCancellation(ctx, cfg.cancellationProvider)
+			return count, false
 		}
 	}
 }
@@ -33,8 +49,11 @@ func Drain[T any](inc <-chan T, maxWait time.Duration) (int, bool) {
 // DrainValues returns the values drained from the channel, and a bool that is true when exiting due to
 // the input channel being drained and closed or false when exiting due to waiting for the `maxWait` duration.
 // When `maxWait <= 0`, Drain will wait forever, and only exit when the input channel is closed.
-func DrainValues[T any](inc <-chan T, maxWait time.Duration) ([]T, bool) {
-	ticker := internalTime.NewTicker(maxWait)
+func DrainValues[T any](inc <-chan T, maxWait time.Duration, opts ...Option[DrainConfig]) ([]T, bool) {
+	cfg := parseOpts(opts...)
+
+	ctx := contextOrBackground(cfg.ctx)
+	ticker := internalTime.NewTicker(clockOrReal(cfg.clock), maxWait)
 	defer ticker.Stop()
 
 	values := []T{}
@@ -48,6 +67,9 @@ func DrainValues[T any](inc <-chan T, maxWait time.Duration) ([]T, bool) {
 			values = append(values, val)
 		case <-ticker.C:
 			return values, false
+		case <-ctx.Done():
+			tryProvideCancellation(ctx, cfg.cancellationProvider)
+			return values, false
 		}
 	}
 }
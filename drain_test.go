@@ -1,10 +1,12 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -75,3 +77,41 @@ func TestDrainValuesReturnsFalseWhenMaxWaitElapses(t *testing.T) {
 	require.Empty(t, values)
 	require.False(t, drained)
 }
+
+func TestDrainClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	defer close(in)
+	in <- 1
+
+	clk := clock.NewManual(time.Now())
+	go func() {
+		clk.BlockUntil(1)
+		clk.Advance(time.Minute)
+	}()
+
+	values, drained := channels.DrainValues(in, time.Minute,
+		channels.ClockOption[channels.DrainConfig](clk),
+	)
+	require.Equal(t, []int{1}, values)
+	require.False(t, drained)
+}
+
+func TestDrainContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	defer close(in)
+	in <- 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond)
+		cancel()
+	}()
+
+	count, drained := channels.Drain(in, 0, channels.ContextOption[channels.DrainConfig](ctx))
+	require.Equal(t, 1, count)
+	require.False(t, drained)
+}
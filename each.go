@@ -1,31 +1,74 @@
 package channels
 
 import (
-	"time"
-
+	"context"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type EachConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[Stats]
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	workers              int
+	order                OutputOrder
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 func Each[T any](inc <-chan T, eachFn func(T), opts ...Option[EachConfig]) {
 	cfg := parseOpts(opts...)
 
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(EachOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+	workers := cfg.workers
+	order := cfg.order
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer panics.handle()
+		defer tryProvideCancellation(ctx, cancellationProvider)
+
+		processConcurrently(ctx, inc, workers, order, clk, panics,
+			func(in T) (struct{}, bool) {
+				eachFn(in)
+				return struct{}{}, true
+			},
+			func(ctx context.Context, result workResult[T, struct{}]) bool {
+				tryProvideStats(Stats{Operator: operator, Name: name, Duration: result.duration, QueueLength: len(inc), BusyWorkers: result.busy}, statsProvider)
+				return true
+			},
+		)
+	}()
+}
 
-		for in := range inc {
-			start := time.Now()
-			eachFn(in)
-			duration := time.Since(start)
+// EachErr is like Each, but with an `eachFn` that can fail.  When `eachFn` returns an error
+// a Failure describing the input value and error is sent to the provider configured
+// with ErrorProviderOption.
+func EachErr[T any](inc <-chan T, eachFn func(T) error, opts ...Option[EachConfig]) {
+	cfg := parseOpts(opts...)
+	errorProvider := failureProvider[T](cfg.errorProvider)
+	name := cfg.name
+	clk := clockOrReal(cfg.clock)
 
-			tryProvideStats(Stats{Duration: duration, QueueLength: len(inc)}, statsProvider)
+	Each(inc, func(in T) {
+		if err := eachFn(in); err != nil {
+			tryProvideFailure(Failure[T]{Operator: EachErrOperator, Name: name, Input: in, Err: err, Time: clk.Now()}, errorProvider)
 		}
-	}()
+	}, withOperator(EachErrOperator, opts)...)
 }
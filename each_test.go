@@ -1,10 +1,15 @@
 package channels_test
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
@@ -76,3 +81,104 @@ func TestEachProviderOptionWithReportStats(t *testing.T) {
 	require.GreaterOrEqual(t, stats[0].Duration, 2*time.Millisecond)
 	require.Equal(t, 1, stats[0].QueueLength)
 }
+
+func TestEachContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	var mu sync.Mutex
+	values := []int{}
+	channels.Each(in,
+		func(i int) {
+			mu.Lock()
+			defer mu.Unlock()
+			values = append(values, i)
+		},
+		channels.ContextOption[channels.EachConfig](ctx),
+		channels.CancellationProviderOption[channels.EachConfig](provider),
+	)
+
+	cancel()
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+
+	in <- 1
+	time.Sleep(1 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Empty(t, values)
+	require.Len(t, in, 1)
+}
+
+func TestEachConcurrencyOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	for i := 0; i < cap(in); i++ {
+		in <- i
+	}
+	close(in)
+
+	var sum atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(cap(in))
+
+	channels.Each(in,
+		func(i int) {
+			defer wg.Done()
+			sum.Add(int32(i))
+		},
+		channels.ConcurrencyOption[channels.EachConfig](3, channels.UnorderedOutput),
+	)
+
+	wg.Wait()
+	require.Equal(t, int32(45), sum.Load())
+}
+
+func TestEachErr(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.Failure[int]](0)
+	defer provider.Close()
+
+	failed := errors.New("failed")
+	channels.EachErr(in,
+		func(i int) error { return failed },
+		channels.ErrorProviderOption[channels.EachConfig](provider),
+	)
+
+	in <- 1
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.EachErrOperator, failure.Operator)
+	require.Equal(t, 1, failure.Input)
+	require.ErrorIs(t, failure.Err, failed)
+}
+
+func TestEachErrClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	provider, receiver := providers.NewProvider[channels.Failure[int]](0)
+	defer provider.Close()
+
+	channels.EachErr(in,
+		func(i int) error { return errors.New("failed") },
+		channels.ClockOption[channels.EachConfig](clk),
+		channels.ErrorProviderOption[channels.EachConfig](provider),
+	)
+
+	in <- 1
+	require.Equal(t, clk.Now(), (<-receiver.Channel()).Time)
+}
@@ -1,14 +1,176 @@
 package channels
 
-import "github.com/jonabc/channels/providers"
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
 
-func tryHandlePanic(provider providers.Provider[any]) {
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+// Failure describes a value of type T that an operation failed to process.
+type Failure[T any] struct {
+	Operator OperatorKind
+	Name     string
+	Input    T
+	Err      error
+	Time     time.Time
+}
+
+type PanicRecovery byte
+
+const (
+	// A panic stops the operation, closing its output channels.
+	StopOnPanic PanicRecovery = iota
+	// A panic is recovered while processing a single value, and the operation
+	// continues with the next value.
+	ContinueOnPanic
+)
+
+// RecoveredPanic is sent to panic providers when a panic is recovered with ContinueOnPanic.
+type RecoveredPanic struct {
+	// The value passed to panic
+	Value any
+	// The value being processed when the panic occurred
+	Input any
+	// The stack trace of the goroutine that panicked
+	Stack []byte
+}
+
+// PanicInfo describes a panic recovered from a channels function.
+type PanicInfo struct {
+	// The channels function that panicked
+	Operator OperatorKind
+	// The name assigned to the channels function with NameOption
+	Name string
+	// The value passed to panic
+	Value any
+	// The stack trace of the goroutine that panicked
+	Stack []byte
+	// The value being processed when the panic occurred, if available.  Input is
+	// only available for panics recovered with ContinueOnPanic.
+	Input any
+	// The time the panic was recovered
+	Time time.Time
+}
+
+// panicHandler reports panics from an operation to the configured panic providers.
+type panicHandler struct {
+	operator     OperatorKind
+	name         string
+	provider     providers.Provider[any]
+	infoProvider providers.Provider[PanicInfo]
+	recovery     PanicRecovery
+	clock        clock.Clock
+}
+
+func newPanicHandler(operator OperatorKind, name string, provider providers.Provider[any], infoProvider providers.Provider[PanicInfo], recovery PanicRecovery, clk clock.Clock) *panicHandler {
+	return &panicHandler{
+		operator:     operator,
+		name:         name,
+		provider:     provider,
+		infoProvider: infoProvider,
+		recovery:     recovery,
+		clock:        clockOrReal(clk),
+	}
+}
+
+// handle recovers a panic that stops an operation's goroutine.  handle must
+// be deferred directly by the goroutine, and is deferred last so that the panic
+// is reported before the goroutine's output channels are closed.
+func (h *panicHandler) handle() {
 	// don't handle the panic if a panic provider isn't provided
-	if provider == nil {
+	if h.provider == nil && h.infoProvider == nil {
 		return
 	}
 
 	if err := recover(); err != nil {
-		provider.Provide(err)
+		if h.provider != nil {
+			h.provider.Provide(err)
+		}
+
+		tryProvidePanicInfo(PanicInfo{Operator: h.operator, Name: h.name, Value: err, Stack: debug.Stack(), Time: h.clock.Now()}, h.infoProvider)
+	}
+}
+
+// tryRecover calls `fn`.  When the handler's recovery is ContinueOnPanic, a panic from `fn` is
+// recovered and reported to the panic providers along with the input value being processed.
+// Returns false if a panic was recovered.
+func (h *panicHandler) tryRecover(input any, fn func()) (ok bool) {
+	if h.recovery != ContinueOnPanic {
+		fn()
+		return true
+	}
+
+	defer func() {
+		if err := recover(); err != nil {
+			ok = false
+			stack := debug.Stack()
+			if h.provider != nil {
+				h.provider.Provide(RecoveredPanic{Value: err, Input: input, Stack: stack})
+			}
+
+			tryProvidePanicInfo(PanicInfo{Operator: h.operator, Name: h.name, Value: err, Stack: stack, Input: input, Time: h.clock.Now()}, h.infoProvider)
+		}
+	}()
+
+	fn()
+	return true
+}
+
+func tryProvidePanicInfo(info PanicInfo, provider providers.Provider[PanicInfo]) {
+	if provider == nil {
+		return
+	}
+
+	provider.Provide(info)
+}
+
+// failureProvider returns the provider configured with ErrorProviderOption as a
+// provider of failures for `T` values.  A provider of failures for any values receives
+// failures with the input value stored as an any.  failureProvider panics if the
+// provider receives failures for a different type of value.
+func failureProvider[T any](provider any) providers.Provider[Failure[T]] {
+	switch provider := provider.(type) {
+	case nil:
+		return nil
+	case providers.Provider[Failure[T]]:
+		return provider
+	case providers.Provider[Failure[any]]:
+		return &anyFailureProvider[T]{provider: provider}
+	default:
+		panic(fmt.Sprintf("channels: error provider %T doesn't receive %T or %T", provider, Failure[T]{}, Failure[any]{}))
+	}
+}
+
+// anyFailureProvider forwards failures for `T` values to a provider of failures
+// for any values.
+type anyFailureProvider[T any] struct {
+	provider providers.Provider[Failure[any]]
+}
+
+func (p *anyFailureProvider[T]) IsClosed() bool {
+	return p.provider.IsClosed()
+}
+
+// Close doesn't close the underlying provider, which is owned by the caller of ErrorProviderOption.
+func (p *anyFailureProvider[T]) Close() {}
+
+func (p *anyFailureProvider[T]) Provide(failure Failure[T]) bool {
+	return p.provider.Provide(Failure[any]{
+		Operator: failure.Operator,
+		Name:     failure.Name,
+		Input:    failure.Input,
+		Err:      failure.Err,
+		Time:     failure.Time,
+	})
+}
+
+func tryProvideFailure[T any](failure Failure[T], provider providers.Provider[Failure[T]]) {
+	if provider == nil {
+		return
 	}
+
+	provider.Provide(failure)
 }
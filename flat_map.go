@@ -1,16 +1,30 @@
 package channels
 
 import (
+	"context"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 // FlatMapConfig contains user configurable options for the FlatMap functions
 type FlatMapConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[Stats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	workers              int
+	order                OutputOrder
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 // FlatMap reads values from the input channel and applies the provided `mapFn`
@@ -22,26 +36,42 @@ func FlatMap[TIn any, TOut any, TOutSlice []TOut](inc <-chan TIn, mapFn func(TIn
 	cfg := parseOpts(opts...)
 
 	outc := make(chan TOut, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(FlatMapOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+	workers := cfg.workers
+	order := cfg.order
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
-		for in := range inc {
-			start := time.Now()
-			outSlice, ok := mapFn(in)
-			duration := time.Since(start)
-
-			if ok {
-				for _, out := range outSlice {
-					outc <- out
+		processConcurrently(ctx, inc, workers, order, clk, panics, mapFn,
+			func(ctx context.Context, result workResult[TIn, TOutSlice]) bool {
+				var sendWait time.Duration
+				if result.ok {
+					for _, out := range result.out {
+						wait, ok := timedSend(ctx, clk, outc, out)
+						if !ok {
+							return false
+						}
+						sendWait += wait
+					}
 				}
-			}
 
-			tryProvideStats(Stats{Duration: duration, QueueLength: len(inc)}, statsProvider)
-		}
+				tryProvideStats(Stats{Operator: operator, Name: name, Duration: result.duration, QueueLength: len(inc), BusyWorkers: result.busy, SendWait: sendWait, OutputLength: len(outc)}, statsProvider)
+				return true
+			},
+		)
 	}()
 
 	return outc
@@ -60,3 +90,23 @@ func FlatMapValues[TIn any, TOut any, TOutSlice []TOut](inc <-chan TIn, mapFn fu
 
 	return result
 }
+
+// FlatMapErr is like FlatMap, but with a `mapFn` that can fail.  When `mapFn` returns an error
+// no values are written to the output channel, and a Failure describing the input value and
+// error is sent to the provider configured with ErrorProviderOption.
+func FlatMapErr[TIn any, TOut any, TOutSlice []TOut](inc <-chan TIn, mapFn func(TIn) (TOutSlice, error), opts ...Option[FlatMapConfig]) <-chan TOut {
+	cfg := parseOpts(opts...)
+	errorProvider := failureProvider[TIn](cfg.errorProvider)
+	name := cfg.name
+	clk := clockOrReal(cfg.clock)
+
+	return FlatMap(inc, func(in TIn) (TOutSlice, bool) {
+		out, err := mapFn(in)
+		if err != nil {
+			tryProvideFailure(Failure[TIn]{Operator: FlatMapErrOperator, Name: name, Input: in, Err: err, Time: clk.Now()}, errorProvider)
+			return out, false
+		}
+
+		return out, true
+	}, withOperator(FlatMapErrOperator, opts)...)
+}
@@ -1,6 +1,8 @@
 package channels_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -83,6 +85,36 @@ func TestFlatMapProviderOptionWithReportPanics(t *testing.T) {
 	require.Equal(t, "panic!", <-receiver.Channel())
 }
 
+func TestFlatMapReportsPanicsBeforeClosingOutput(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[any](0)
+	defer provider.Close()
+
+	out := channels.FlatMap(in,
+		func(i int) ([]int, bool) { panic("panic!") },
+		channels.PanicProviderOption[channels.FlatMapConfig](provider),
+	)
+
+	in <- 1
+
+	require.Never(t, func() bool {
+		select {
+		case <-out:
+			return true
+		default:
+			return false
+		}
+	}, 20*time.Millisecond, time.Millisecond)
+
+	require.Equal(t, "panic!", <-receiver.Channel())
+	_, ok := <-out
+	require.False(t, ok)
+}
+
 func TestFlatMapProviderOptionWithReportStats(t *testing.T) {
 	t.Parallel()
 
@@ -111,3 +143,85 @@ func TestFlatMapProviderOptionWithReportStats(t *testing.T) {
 	require.GreaterOrEqual(t, stats[0].Duration, 2*time.Millisecond)
 	require.Equal(t, stats[0].QueueLength, 1)
 }
+
+func TestFlatMapContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.FlatMap(in,
+		func(i int) ([]int, bool) { return []int{i, i}, true },
+		channels.ContextOption[channels.FlatMapConfig](ctx),
+		channels.CancellationProviderOption[channels.FlatMapConfig](provider),
+	)
+
+	in <- 1
+	require.Equal(t, 1, <-out)
+
+	cause := errors.New("cancelled")
+	cancel(cause)
+
+	require.Equal(t, cause, <-receiver.Channel())
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestFlatMapConcurrencyOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	for i := 0; i < cap(in); i++ {
+		in <- i
+	}
+	close(in)
+
+	out := channels.FlatMapValues(in,
+		func(i int) ([]int, bool) {
+			time.Sleep(time.Duration(10-i) * 100 * time.Microsecond)
+			return []int{i, i}, true
+		},
+		channels.ConcurrencyOption[channels.FlatMapConfig](3, channels.OrderedOutput),
+	)
+
+	expected := []int{}
+	for i := 0; i < 10; i++ {
+		expected = append(expected, i, i)
+	}
+	require.Equal(t, expected, out)
+}
+
+func TestFlatMapErr(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewProvider[channels.Failure[int]](1)
+	defer provider.Close()
+
+	failed := errors.New("odd")
+	out := channels.FlatMapErr(in,
+		func(i int) ([]int, error) {
+			if i%2 == 1 {
+				return nil, failed
+			}
+			return []int{i, i}, nil
+		},
+		channels.ErrorProviderOption[channels.FlatMapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	close(in)
+
+	require.Equal(t, []int{2, 2}, []int{<-out, <-out})
+
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.FlatMapErrOperator, failure.Operator)
+	require.Equal(t, 1, failure.Input)
+	require.ErrorIs(t, failure.Err, failed)
+}
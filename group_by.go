@@ -0,0 +1,199 @@
+package channels
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+// ErrGroupLimitReached is the error reported in a Failure for values dropped by GroupBy
+// because the group limit was reached.
+var ErrGroupLimitReached = errors.New("group limit reached")
+
+type GroupLimitPolicy byte
+
+const (
+	// The group that least recently received a value is closed to make room for a new group.
+	EvictLeastRecentGroup GroupLimitPolicy = iota
+	// Values for new groups are dropped, and sent to the provider configured with
+	// ErrorProviderOption, until an existing group is closed.
+	DropNewGroups
+)
+
+type GroupByConfig struct {
+	panicProvider        providers.Provider[any]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	clock                clock.Clock
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	groupCapacity        int
+	idleTimeout          time.Duration
+	limit                int
+	limitPolicy          GroupLimitPolicy
+}
+
+// Group is a sub-channel of values with the same key, created by GroupBy.
+type Group[K comparable, V any] struct {
+	Key    K
+	Values <-chan V
+}
+
+type group[K comparable, V any] struct {
+	key      K
+	c        chan V
+	lastSeen time.Time
+}
+
+// GroupBy reads values from the input channel and writes each value to a group channel
+// for the value's key, as returned by the value's Key() function.  The first time a
+// value is read for a key, a new Group is written to the output channel before the value
+// is written to the group's channel.  Writing a value to a group's channel blocks until
+// the group's channel can be written to, so every group must be read from.
+//
+// Groups are open until the input channel is closed by default.  GroupIdleTimeoutOption
+// closes groups that haven't received a value within a timeout, and GroupLimitOption
+// limits the number of open groups.  A value for the key of a closed group creates a
+// new Group.  The output channel and group channels are unbuffered by default, see
+// GroupCapacityOption to buffer group channels.  The output channel and every group
+// channel will be closed when the input channel is closed and drained.
+func GroupBy[K comparable, V Keyable[K]](inc <-chan V, opts ...Option[GroupByConfig]) <-chan Group[K, V] {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan Group[K, V], cfg.capacity)
+	operator := cfg.operator.or(GroupByOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	cancellationProvider := cfg.cancellationProvider
+	errorProvider := failureProvider[V](cfg.errorProvider)
+	groupCapacity := cfg.groupCapacity
+	idleTimeout := cfg.idleTimeout
+	limit := cfg.limit
+	limitPolicy := cfg.limitPolicy
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	groups := make(map[K]*list.Element)
+	// groups ordered by the time they last received a value, least recent first
+	recent := list.New()
+
+	var timer clock.Timer
+	var timeout <-chan time.Time
+	if idleTimeout > 0 {
+		timer = clk.NewTimer(idleTimeout)
+		timer.Stop()
+	}
+
+	// arm starts the idle timer for the least recent group, if the timer isn't running
+	arm := func() {
+		if timer == nil || timeout != nil || recent.Len() == 0 {
+			return
+		}
+
+		lastSeen := recent.Front().Value.(*group[K, V]).lastSeen
+		timer.Reset(lastSeen.Add(idleTimeout).Sub(clk.Now()))
+		timeout = timer.C()
+	}
+
+	closeGroup := func(elem *list.Element) {
+		g := recent.Remove(elem).(*group[K, V])
+		delete(groups, g.key)
+		close(g.c)
+	}
+
+	closeIdleGroups := func() {
+		now := clk.Now()
+		for recent.Len() > 0 {
+			front := recent.Front()
+			if now.Sub(front.Value.(*group[K, V]).lastSeen) < idleTimeout {
+				break
+			}
+			closeGroup(front)
+		}
+	}
+
+	// lookup returns the group for `key`, creating it if needed.  Returns nil if
+	// the value should be dropped, and false if the context is done.
+	lookup := func(in V, key K, now time.Time) (*group[K, V], bool) {
+		if elem, ok := groups[key]; ok {
+			return elem.Value.(*group[K, V]), true
+		}
+
+		if limit > 0 && len(groups) >= limit {
+			if limitPolicy == DropNewGroups {
+				tryProvideFailure(Failure[V]{Operator: operator, Name: name, Input: in, Err: ErrGroupLimitReached, Time: now}, errorProvider)
+				return nil, true
+			}
+			closeGroup(recent.Front())
+		}
+
+		g := &group[K, V]{key: key, c: make(chan V, groupCapacity)}
+		groups[key] = recent.PushBack(g)
+		return g, send(ctx, outc, Group[K, V]{Key: key, Values: g.c})
+	}
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer func() {
+			for recent.Len() > 0 {
+				closeGroup(recent.Front())
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		defer panics.handle()
+
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case in, ok := <-inc:
+				if !ok {
+					return
+				}
+
+				now := clk.Now()
+				g, ok := lookup(in, in.Key(), now)
+				if !ok {
+					return
+				}
+				if g == nil {
+					continue
+				}
+
+				g.lastSeen = now
+				recent.MoveToBack(groups[g.key])
+				arm()
+
+				if !send(ctx, g.c, in) {
+					return
+				}
+			case <-timeout:
+				timeout = nil
+				closeIdleGroups()
+				arm()
+			}
+		}
+	}()
+
+	return outc
+}
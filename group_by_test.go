@@ -0,0 +1,175 @@
+package channels_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+type groupedValue struct {
+	key string
+	val int
+}
+
+func (v groupedValue) Key() string {
+	return v.key
+}
+
+func collectGroup(t *testing.T, group channels.Group[string, groupedValue]) []int {
+	t.Helper()
+
+	values := []int{}
+	for v := range group.Values {
+		require.Equal(t, group.Key, v.key)
+		values = append(values, v.val)
+	}
+	return values
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	inc := make(chan groupedValue)
+	out := channels.GroupBy[string](inc)
+	require.Equal(t, 0, cap(out))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := map[string][]int{}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for group := range out {
+			wg.Add(1)
+			go func(group channels.Group[string, groupedValue]) {
+				defer wg.Done()
+				values := collectGroup(t, group)
+
+				mu.Lock()
+				defer mu.Unlock()
+				results[group.Key] = values
+			}(group)
+		}
+	}()
+
+	inc <- groupedValue{key: "a", val: 1}
+	inc <- groupedValue{key: "b", val: 1}
+	inc <- groupedValue{key: "a", val: 2}
+	inc <- groupedValue{key: "b", val: 2}
+	inc <- groupedValue{key: "c", val: 1}
+	close(inc)
+	wg.Wait()
+
+	require.Equal(t, map[string][]int{
+		"a": {1, 2},
+		"b": {1, 2},
+		"c": {1},
+	}, results)
+}
+
+func TestGroupByGroupIdleTimeoutOption(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+
+	inc := make(chan groupedValue)
+	defer close(inc)
+
+	out := channels.GroupBy[string](inc,
+		channels.ClockOption[channels.GroupByConfig](clk),
+		channels.GroupCapacityOption(10),
+		channels.GroupIdleTimeoutOption(time.Second),
+	)
+
+	inc <- groupedValue{key: "a", val: 1}
+	group := <-out
+	require.Equal(t, "a", group.Key)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	require.Equal(t, []int{1}, collectGroup(t, group))
+
+	// a value for the key of a closed group creates a new group
+	inc <- groupedValue{key: "a", val: 2}
+	group = <-out
+	require.Equal(t, "a", group.Key)
+	require.Equal(t, groupedValue{key: "a", val: 2}, <-group.Values)
+}
+
+func TestGroupByGroupLimitOptionEvictsLeastRecentGroup(t *testing.T) {
+	t.Parallel()
+
+	inc := make(chan groupedValue)
+	defer close(inc)
+
+	out := channels.GroupBy[string](inc,
+		channels.GroupCapacityOption(10),
+		channels.GroupLimitOption(2, channels.EvictLeastRecentGroup),
+	)
+
+	inc <- groupedValue{key: "a", val: 1}
+	a := <-out
+	inc <- groupedValue{key: "b", val: 1}
+	b := <-out
+	inc <- groupedValue{key: "a", val: 2}
+
+	// b received a value least recently, and is closed for the new group
+	inc <- groupedValue{key: "c", val: 1}
+	c := <-out
+	require.Equal(t, "c", c.Key)
+	require.Equal(t, []int{1}, collectGroup(t, b))
+
+	require.Equal(t, groupedValue{key: "a", val: 1}, <-a.Values)
+	require.Equal(t, groupedValue{key: "a", val: 2}, <-a.Values)
+	require.Equal(t, groupedValue{key: "c", val: 1}, <-c.Values)
+}
+
+func TestGroupByGroupLimitOptionDropsNewGroups(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.Failure[groupedValue]](1)
+	defer provider.Close()
+
+	inc := make(chan groupedValue)
+	out := channels.GroupBy[string](inc,
+		channels.GroupCapacityOption(10),
+		channels.GroupLimitOption(1, channels.DropNewGroups),
+		channels.ErrorProviderOption[channels.GroupByConfig](provider),
+	)
+
+	inc <- groupedValue{key: "a", val: 1}
+	a := <-out
+	inc <- groupedValue{key: "b", val: 1}
+
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.GroupByOperator, failure.Operator)
+	require.Equal(t, groupedValue{key: "b", val: 1}, failure.Input)
+	require.ErrorIs(t, failure.Err, channels.ErrGroupLimitReached)
+
+	close(inc)
+	require.Equal(t, []int{1}, collectGroup(t, a))
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestGroupByContextOption(t *testing.T) {
+	t.Parallel()
+
+	inc := make(chan groupedValue)
+	defer close(inc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.GroupBy[string](inc, channels.ContextOption[channels.GroupByConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
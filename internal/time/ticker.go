@@ -1,23 +1,27 @@
 package time
 
-import "time"
+import (
+	"time"
+
+	"github.com/jonabc/channels/clock"
+)
 
 type Ticker struct {
-	internal *time.Ticker
+	internal clock.Ticker
 	C        <-chan time.Time
 }
 
-func NewTicker(interval time.Duration) *Ticker {
-	var internal *time.Ticker
-	var c <-chan time.Time
+func NewTicker(c clock.Clock, interval time.Duration) *Ticker {
+	var internal clock.Ticker
+	var ch <-chan time.Time
 	if interval > 0 {
-		internal = time.NewTicker(interval)
-		c = internal.C
+		internal = c.NewTicker(interval)
+		ch = internal.C()
 	}
 
 	return &Ticker{
 		internal: internal,
-		C:        c,
+		C:        ch,
 	}
 }
 
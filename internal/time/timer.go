@@ -1,23 +1,27 @@
 package time
 
-import "time"
+import (
+	"time"
+
+	"github.com/jonabc/channels/clock"
+)
 
 type Timer struct {
-	internal *time.Timer
+	internal clock.Timer
 	C        <-chan time.Time
 }
 
-func NewTimer(interval time.Duration) *Timer {
-	var internal *time.Timer
-	var c <-chan time.Time
+func NewTimer(c clock.Clock, interval time.Duration) *Timer {
+	var internal clock.Timer
+	var ch <-chan time.Time
 	if interval > 0 {
-		internal = time.NewTimer(interval)
-		c = internal.C
+		internal = c.NewTimer(interval)
+		ch = internal.C()
 	}
 
 	return &Timer{
 		internal: internal,
-		C:        c,
+		C:        ch,
 	}
 }
 
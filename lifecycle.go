@@ -0,0 +1,112 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+type LifecycleState byte
+
+const (
+	// The channels function has started processing values.
+	OperatorStarted LifecycleState = iota
+	// The channels function has stopped processing values, and closed its output channels.
+	OperatorStopped
+)
+
+func (s LifecycleState) String() string {
+	switch s {
+	case OperatorStarted:
+		return "started"
+	case OperatorStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// LifecycleEvent describes a channels function starting or stopping.  Err is set for
+// OperatorStopped events when the function was stopped by its context or stop handle,
+// and is the cause of the context being done, or ErrStopped.
+type LifecycleEvent struct {
+	Operator OperatorKind
+	Name     string
+	State    LifecycleState
+	Err      error
+	Time     time.Time
+}
+
+// lifecycle reports when an operation starts and stops to a lifecycle provider, and
+// ties the operation to a stop handle.
+type lifecycle struct {
+	operator OperatorKind
+	name     string
+	provider providers.Provider[LifecycleEvent]
+	handle   *StopHandle
+	clock    clock.Clock
+	release  func()
+	drains   []func()
+}
+
+func newLifecycle(operator OperatorKind, name string, provider providers.Provider[LifecycleEvent], handle *StopHandle, clk clock.Clock) *lifecycle {
+	return &lifecycle{operator: operator, name: name, provider: provider, handle: handle, clock: clockOrReal(clk), release: func() {}}
+}
+
+// bind returns the context for the operation, which is done when `ctx` is done or
+// the operation's stop handle is stopped.
+func (l *lifecycle) bind(ctx context.Context) context.Context {
+	ctx, l.release = l.handle.bind(ctx)
+	return ctx
+}
+
+// drainOnStop registers an input channel to be drained when the operation stops,
+// if its stop handle was stopped with DrainInputOnStop.
+func drainOnStop[T any](l *lifecycle, inc <-chan T) {
+	if l.handle == nil {
+		return
+	}
+
+	l.drains = append(l.drains, func() { drainStopped(l.handle, inc) })
+}
+
+func (l *lifecycle) start() {
+	if l.provider == nil {
+		return
+	}
+
+	l.provider.Provide(LifecycleEvent{Operator: l.operator, Name: l.name, State: OperatorStarted, Time: l.clock.Now()})
+}
+
+// stop drains the operation's input channels when needed, and reports that the
+// operation stopped with the context's cause if the context is done.  An operation
+// that stops after its stop handle was stopped reports ErrStopped even when it saw its
+// input channel close first, e.g. when an upstream function using the same handle was
+// stopped before the handle's cancellation reached this operation's context.
+func (l *lifecycle) stop(ctx context.Context) {
+	defer l.release()
+
+	var wg sync.WaitGroup
+	for _, drain := range l.drains {
+		wg.Add(1)
+		go func(drain func()) {
+			defer wg.Done()
+			drain()
+		}(drain)
+	}
+	wg.Wait()
+
+	if l.provider == nil {
+		return
+	}
+
+	err := context.Cause(ctx)
+	if err == nil {
+		err = l.handle.cause()
+	}
+
+	l.provider.Provide(LifecycleEvent{Operator: l.operator, Name: l.name, State: OperatorStopped, Err: err, Time: l.clock.Now()})
+}
@@ -1,15 +1,29 @@
 package channels
 
 import (
+	"context"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type MapConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[Stats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	workers              int
+	order                OutputOrder
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 // Map reads values from the input channel and applies the provided `mapFn`
@@ -21,23 +35,39 @@ func Map[TIn any, TOut any](inc <-chan TIn, mapFn func(TIn) (TOut, bool), opts .
 	cfg := parseOpts(opts...)
 
 	outc := make(chan TOut, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(MapOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+	workers := cfg.workers
+	order := cfg.order
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
-		for in := range inc {
-			start := time.Now()
-			val, ok := mapFn(in)
-			duration := time.Since(start)
-			if ok {
-				outc <- val
-			}
+		processConcurrently(ctx, inc, workers, order, clk, panics, mapFn,
+			func(ctx context.Context, result workResult[TIn, TOut]) bool {
+				var sendWait time.Duration
+				if result.ok {
+					var ok bool
+					if sendWait, ok = timedSend(ctx, clk, outc, result.out); !ok {
+						return false
+					}
+				}
 
-			tryProvideStats(Stats{Duration: duration, QueueLength: len(inc)}, statsProvider)
-		}
+				tryProvideStats(Stats{Operator: operator, Name: name, Duration: result.duration, QueueLength: len(inc), BusyWorkers: result.busy, SendWait: sendWait, OutputLength: len(outc)}, statsProvider)
+				return true
+			},
+		)
 	}()
 
 	return outc
@@ -56,3 +86,23 @@ func MapValues[TIn any, TOut any](inc <-chan TIn, mapFn func(TIn) (TOut, bool),
 
 	return result
 }
+
+// MapErr is like Map, but with a `mapFn` that can fail.  When `mapFn` returns an error
+// the value is not written to the output channel, and a Failure describing the input
+// value and error is sent to the provider configured with ErrorProviderOption.
+func MapErr[TIn any, TOut any](inc <-chan TIn, mapFn func(TIn) (TOut, error), opts ...Option[MapConfig]) <-chan TOut {
+	cfg := parseOpts(opts...)
+	errorProvider := failureProvider[TIn](cfg.errorProvider)
+	name := cfg.name
+	clk := clockOrReal(cfg.clock)
+
+	return Map(inc, func(in TIn) (TOut, bool) {
+		out, err := mapFn(in)
+		if err != nil {
+			tryProvideFailure(Failure[TIn]{Operator: MapErrOperator, Name: name, Input: in, Err: err, Time: clk.Now()}, errorProvider)
+			return out, false
+		}
+
+		return out, true
+	}, withOperator(MapErrOperator, opts)...)
+}
@@ -1,10 +1,14 @@
 package channels_test
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
@@ -109,3 +113,543 @@ func TestMapProviderOptionWithReportStats(t *testing.T) {
 	require.GreaterOrEqual(t, stats[0].Duration, 2*time.Millisecond)
 	require.Equal(t, stats[0].QueueLength, 1)
 }
+
+// steppingClock is a manual clock that moves forward by `step` each time it's read,
+// so a duration measured between two reads is always exactly `step`.
+type steppingClock struct {
+	*clock.Manual
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	now := c.Manual.Now()
+	c.Manual.Advance(c.step)
+	return now
+}
+
+func TestMapStatsReportOutputBackpressure(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := &steppingClock{Manual: clock.NewManual(time.Now()), step: time.Second}
+	provider, receiver := providers.NewProvider[channels.Stats](2)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.ChannelCapacityOption[channels.MapConfig](1),
+		channels.ClockOption[channels.MapConfig](clk),
+		channels.StatsProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+
+	first := <-receiver.Channel()
+	require.Equal(t, time.Second, first.Duration)
+	require.Equal(t, time.Second, first.SendWait)
+	require.Equal(t, 1, first.OutputLength)
+
+	require.Equal(t, 1, <-out)
+	second := <-receiver.Channel()
+	require.Equal(t, time.Second, second.SendWait)
+	require.Equal(t, 1, second.OutputLength)
+}
+
+func TestMapContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.ContextOption[channels.MapConfig](ctx),
+		channels.CancellationProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+
+	// cancelling the context unblocks the pending write of 2
+	cause := errors.New("cancelled")
+	cancel(cause)
+
+	require.Equal(t, cause, <-receiver.Channel())
+	_, ok := <-out
+	require.False(t, ok)
+	require.Len(t, in, 0)
+}
+
+func TestMapLifecycleProviderOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewProvider[channels.LifecycleEvent](2)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.NameOption[channels.MapConfig]("mapper"),
+		channels.LifecycleProviderOption[channels.MapConfig](provider),
+	)
+
+	started := <-receiver.Channel()
+	require.Equal(t, channels.MapOperator, started.Operator)
+	require.Equal(t, "mapper", started.Name)
+	require.Equal(t, channels.OperatorStarted, started.State)
+	require.NoError(t, started.Err)
+	require.False(t, started.Time.IsZero())
+
+	in <- 1
+	require.Equal(t, 1, <-out)
+	close(in)
+
+	_, ok := <-out
+	require.False(t, ok)
+
+	stopped := <-receiver.Channel()
+	require.Equal(t, channels.MapOperator, stopped.Operator)
+	require.Equal(t, "mapper", stopped.Name)
+	require.Equal(t, channels.OperatorStopped, stopped.State)
+	require.NoError(t, stopped.Err)
+	require.False(t, stopped.Time.Before(started.Time))
+}
+
+func TestMapLifecycleProviderOptionWithClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+
+	clk := clock.NewManual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	provider, receiver := providers.NewProvider[channels.LifecycleEvent](2)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.ClockOption[channels.MapConfig](clk),
+		channels.LifecycleProviderOption[channels.MapConfig](provider),
+	)
+
+	require.Equal(t, clk.Now(), (<-receiver.Channel()).Time)
+
+	clk.Advance(time.Minute)
+	close(in)
+	_, ok := <-out
+	require.False(t, ok)
+	require.Equal(t, clk.Now(), (<-receiver.Channel()).Time)
+}
+
+func TestMapLifecycleProviderOptionWithContext(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	provider, receiver := providers.NewProvider[channels.LifecycleEvent](2)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.ContextOption[channels.MapConfig](ctx),
+		channels.LifecycleProviderOption[channels.MapConfig](provider),
+	)
+	require.Equal(t, channels.OperatorStarted, (<-receiver.Channel()).State)
+
+	cause := errors.New("cancelled")
+	cancel(cause)
+
+	_, ok := <-out
+	require.False(t, ok)
+
+	stopped := <-receiver.Channel()
+	require.Equal(t, channels.OperatorStopped, stopped.State)
+	require.Equal(t, cause, stopped.Err)
+}
+
+func TestMapConcurrencyOptionWithOrderedOutput(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	for i := 0; i < cap(in); i++ {
+		in <- i
+	}
+	close(in)
+
+	out := channels.MapValues(in,
+		func(i int) (int, bool) {
+			// later values finish faster than earlier values
+			time.Sleep(time.Duration(100-i) * 10 * time.Microsecond)
+			return i * 2, true
+		},
+		channels.ConcurrencyOption[channels.MapConfig](4, channels.OrderedOutput),
+	)
+
+	require.Len(t, out, 100)
+	for i, val := range out {
+		require.Equal(t, i*2, val)
+	}
+}
+
+func TestMapConcurrencyOptionWithUnorderedOutput(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ready := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	provider, receiver := providers.NewCollectingProvider[channels.Stats](0)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) {
+			if i > 0 {
+				// wait until all three workers are running
+				wg.Done()
+				<-ready
+			}
+			return i, true
+		},
+		channels.ConcurrencyOption[channels.MapConfig](3, channels.UnorderedOutput),
+		channels.StatsProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	in <- 3
+	wg.Wait()
+
+	// a value can't be processed while all workers are busy
+	in <- 0
+	time.Sleep(1 * time.Millisecond)
+	require.Len(t, in, 1)
+
+	close(ready)
+	require.ElementsMatch(t, []int{0, 1, 2, 3}, []int{<-out, <-out, <-out, <-out})
+
+	stats := []channels.Stats{}
+	for len(stats) < 4 {
+		stats = append(stats, <-receiver.Channel()...)
+	}
+
+	busy := make([]int, len(stats))
+	for i, stat := range stats {
+		busy[i] = stat.BusyWorkers
+	}
+	require.Contains(t, busy, 3)
+}
+
+func TestMapConcurrencyOptionWithReportPanics(t *testing.T) {
+	t.Parallel()
+
+	for _, order := range []channels.OutputOrder{channels.OrderedOutput, channels.UnorderedOutput} {
+		in := make(chan int, 100)
+		defer close(in)
+
+		provider, receiver := providers.NewProvider[any](0)
+		defer provider.Close()
+
+		out := channels.Map(in,
+			func(i int) (int, bool) { panic("panic!") },
+			channels.ConcurrencyOption[channels.MapConfig](2, order),
+			channels.PanicProviderOption[channels.MapConfig](provider),
+		)
+
+		in <- 1
+		require.Equal(t, "panic!", <-receiver.Channel())
+
+		// the remaining worker is stopped and the output channel is closed
+		_, ok := <-out
+		require.False(t, ok)
+	}
+}
+
+func TestMapReportsPanicsBeforeClosingOutput(t *testing.T) {
+	t.Parallel()
+
+	for _, workers := range []int{1, 2} {
+		in := make(chan int, 100)
+		defer close(in)
+
+		provider, receiver := providers.NewProvider[any](0)
+		defer provider.Close()
+
+		out := channels.Map(in,
+			func(i int) (int, bool) { panic("panic!") },
+			channels.ConcurrencyOption[channels.MapConfig](workers, channels.OrderedOutput),
+			channels.PanicProviderOption[channels.MapConfig](provider),
+		)
+
+		in <- 1
+
+		// the output channel isn't closed until the panic is reported
+		require.Never(t, func() bool {
+			select {
+			case <-out:
+				return true
+			default:
+				return false
+			}
+		}, 20*time.Millisecond, time.Millisecond)
+
+		require.Equal(t, "panic!", <-receiver.Channel())
+		_, ok := <-out
+		require.False(t, ok)
+	}
+}
+
+func TestMapErr(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewCollectingProvider[channels.Failure[int]](0)
+	defer provider.Close()
+
+	failed := errors.New("odd")
+	out := channels.MapErr(in,
+		func(i int) (int, error) {
+			if i%2 == 1 {
+				return 0, failed
+			}
+			return i * 10, nil
+		},
+		channels.ErrorProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	in <- 3
+	in <- 4
+	close(in)
+
+	require.Equal(t, 20, <-out)
+	require.Equal(t, 40, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+
+	failures := <-receiver.Channel()
+	require.Len(t, failures, 2)
+	require.Equal(t, channels.MapErrOperator, failures[0].Operator)
+	require.Equal(t, 1, failures[0].Input)
+	require.ErrorIs(t, failures[0].Err, failed)
+	require.False(t, failures[0].Time.IsZero())
+	require.Equal(t, 3, failures[1].Input)
+}
+
+func TestMapErrClockOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	provider, receiver := providers.NewProvider[channels.Failure[int]](0)
+	defer provider.Close()
+
+	channels.MapErr(in,
+		func(i int) (int, error) { return 0, errors.New("failed") },
+		channels.ClockOption[channels.MapConfig](clk),
+		channels.ErrorProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	require.Equal(t, clk.Now(), (<-receiver.Channel()).Time)
+}
+
+func TestMapErrWithAnyErrorProvider(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewProvider[channels.Failure[any]](1)
+	defer provider.Close()
+
+	failed := errors.New("odd")
+	out := channels.MapErr(in,
+		func(i int) (int, error) { return 0, failed },
+		channels.ErrorProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	close(in)
+
+	_, ok := <-out
+	require.False(t, ok)
+
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.MapErrOperator, failure.Operator)
+	require.Equal(t, 1, failure.Input)
+	require.ErrorIs(t, failure.Err, failed)
+}
+
+func TestMapErrPanicsWithMismatchedErrorProvider(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	provider, _ := providers.NewProvider[channels.Failure[string]](1)
+	defer provider.Close()
+
+	require.Panics(t, func() {
+		channels.MapErr(in,
+			func(i int) (int, error) { return i, nil },
+			channels.ErrorProviderOption[channels.MapConfig](provider),
+		)
+	})
+}
+
+func TestMapPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewProvider[any](1)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) {
+			if i == 2 {
+				panic("panic!")
+			}
+			return i, true
+		},
+		channels.PanicProviderOption[channels.MapConfig](provider),
+		channels.PanicRecoveryOption[channels.MapConfig](channels.ContinueOnPanic),
+	)
+
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	require.Equal(t, 1, <-out)
+	require.Equal(t, 3, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+
+	recovered := (<-receiver.Channel()).(channels.RecoveredPanic)
+	require.Equal(t, "panic!", recovered.Value)
+	require.Equal(t, 2, recovered.Input)
+	require.Contains(t, string(recovered.Stack), "map_test.go")
+}
+
+func TestMapPanicRecoveryOptionWithConcurrency(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	for i := 0; i < 10; i++ {
+		in <- i
+	}
+	close(in)
+
+	out := channels.MapValues(in,
+		func(i int) (int, bool) {
+			if i%2 == 0 {
+				panic("panic!")
+			}
+			return i, true
+		},
+		channels.ConcurrencyOption[channels.MapConfig](3, channels.OrderedOutput),
+		channels.PanicRecoveryOption[channels.MapConfig](channels.ContinueOnPanic),
+	)
+
+	require.Equal(t, []int{1, 3, 5, 7, 9}, out)
+}
+
+func TestMapPanicInfoProviderOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.PanicInfo](1)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (bool, bool) { panic("panic!") },
+		channels.PanicInfoProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	info := <-receiver.Channel()
+	require.Equal(t, channels.MapOperator, info.Operator)
+	require.Equal(t, "panic!", info.Value)
+	require.Nil(t, info.Input)
+	require.Contains(t, string(info.Stack), "map_test.go")
+	require.False(t, info.Time.IsZero())
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestMapErrPanicInfoProviderOptionWithPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.PanicInfo](1)
+	defer provider.Close()
+
+	channels.MapErr(in,
+		func(i int) (bool, error) { panic("panic!") },
+		channels.PanicInfoProviderOption[channels.MapConfig](provider),
+		channels.PanicRecoveryOption[channels.MapConfig](channels.ContinueOnPanic),
+	)
+
+	in <- 1
+	info := <-receiver.Channel()
+	require.Equal(t, channels.MapErrOperator, info.Operator)
+	require.Equal(t, "panic!", info.Value)
+	require.Equal(t, 1, info.Input)
+}
+
+func TestMapNameOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	statsProvider, statsReceiver := providers.NewProvider[channels.Stats](1)
+	defer statsProvider.Close()
+
+	panicProvider, panicReceiver := providers.NewProvider[channels.PanicInfo](1)
+	defer panicProvider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) {
+			if i == 2 {
+				panic("panic!")
+			}
+			return i, true
+		},
+		channels.NameOption[channels.MapConfig]("doubler"),
+		channels.StatsProviderOption[channels.MapConfig](statsProvider),
+		channels.PanicInfoProviderOption[channels.MapConfig](panicProvider),
+	)
+
+	in <- 1
+	require.Equal(t, 1, <-out)
+	stats := <-statsReceiver.Channel()
+	require.Equal(t, channels.MapOperator, stats.Operator)
+	require.Equal(t, "doubler", stats.Name)
+
+	in <- 2
+	info := <-panicReceiver.Channel()
+	require.Equal(t, channels.MapOperator, info.Operator)
+	require.Equal(t, "doubler", info.Name)
+}
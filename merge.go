@@ -1,14 +1,28 @@
 package channels
 
 import (
+	"context"
+	"reflect"
 	"sync"
+	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type MergeConfig struct {
-	panicProvider providers.Provider[any]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	statsProvider        providers.Provider[MergeStats]
+	clock                clock.Clock
+	idleTimeout          time.Duration
 }
 
 // Merge merges multiple input channels into a single output channel.  The
@@ -17,24 +31,35 @@ type MergeConfig struct {
 // is unbuffered by default and is closed when all input channels are closed.
 func Merge[T any](chans []<-chan T, opts ...Option[MergeConfig]) <-chan T {
 	cfg := parseOpts(opts...)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(MergeOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	cancellationProvider := cfg.cancellationProvider
 
-	switch len(chans) {
-	case 0:
+	switch {
+	case len(chans) == 0:
 		return nil
-	case 1:
+	case len(chans) == 1 && cfg.ctx == nil && cfg.stopHandle == nil && cfg.lifecycleProvider == nil:
+		// nothing can stop or observe a single input channel, so it's returned as is
 		return chans[0]
 	default:
 		var wg sync.WaitGroup
 		outc := make(chan T, cfg.capacity)
+		lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+		ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
 		i := 0
 
+		for _, c := range chans {
+			drainOnStop(lifecycle, c)
+		}
+		lifecycle.start()
+
 		for len(chans)-i >= 4 {
 			wg.Add(1)
 			go func(i int) {
-				defer tryHandlePanic(panicProvider)
 				defer wg.Done()
-				merge4(outc, chans[i], chans[i+1], chans[i+2], chans[i+3])
+				defer panics.handle()
+				merge4(ctx, outc, chans[i], chans[i+1], chans[i+2], chans[i+3])
 			}(i)
 			i += 4
 		}
@@ -42,9 +67,9 @@ func Merge[T any](chans []<-chan T, opts ...Option[MergeConfig]) <-chan T {
 		for len(chans)-i >= 2 {
 			wg.Add(1)
 			go func(i int) {
-				defer tryHandlePanic(panicProvider)
 				defer wg.Done()
-				merge2(outc, chans[i], chans[i+1])
+				defer panics.handle()
+				merge2(ctx, outc, chans[i], chans[i+1])
 			}(i)
 			i += 2
 		}
@@ -52,10 +77,13 @@ func Merge[T any](chans []<-chan T, opts ...Option[MergeConfig]) <-chan T {
 		for len(chans)-i >= 1 {
 			wg.Add(1)
 			go func(i int) {
-				defer tryHandlePanic(panicProvider)
 				defer wg.Done()
-				for v := range chans[i] {
-					outc <- v
+				defer panics.handle()
+				for {
+					val, ok := receive(ctx, chans[i])
+					if !ok || !send(ctx, outc, val) {
+						return
+					}
 				}
 			}(i)
 			i++
@@ -64,57 +92,166 @@ func Merge[T any](chans []<-chan T, opts ...Option[MergeConfig]) <-chan T {
 		go func() {
 			wg.Wait()
 			close(outc)
+			tryProvideCancellation(ctx, cancellationProvider)
+			lifecycle.stop(ctx)
 		}()
 		return outc
 	}
 }
 
-func merge2[T any](outc chan<- T, inc1, inc2 <-chan T) {
+func merge2[T any](ctx context.Context, outc chan<- T, inc1, inc2 <-chan T) {
 	for inc1 != nil || inc2 != nil {
 		select {
+		case <-ctx.Done():
+			return
 		case val, ok := <-inc1:
 			if !ok {
 				inc1 = nil
-			} else {
-				outc <- val
+			} else if !send(ctx, outc, val) {
+				return
 			}
 		case val, ok := <-inc2:
 			if !ok {
 				inc2 = nil
-			} else {
-				outc <- val
+			} else if !send(ctx, outc, val) {
+				return
 			}
 		}
 	}
 }
 
-func merge4[T any](outc chan<- T, inc1, inc2, inc3, inc4 <-chan T) {
+func merge4[T any](ctx context.Context, outc chan<- T, inc1, inc2, inc3, inc4 <-chan T) {
 	for inc1 != nil || inc2 != nil || inc3 != nil || inc4 != nil {
 		select {
+		case <-ctx.Done():
+			return
 		case val, ok := <-inc1:
 			if !ok {
 				inc1 = nil
-			} else {
-				outc <- val
+			} else if !send(ctx, outc, val) {
+				return
 			}
 		case val, ok := <-inc2:
 			if !ok {
 				inc2 = nil
-			} else {
-				outc <- val
+			} else if !send(ctx, outc, val) {
+				return
 			}
 		case val, ok := <-inc3:
 			if !ok {
 				inc3 = nil
-			} else {
-				outc <- val
+			} else if !send(ctx, outc, val) {
+				return
 			}
 		case val, ok := <-inc4:
 			if !ok {
 				inc4 = nil
-			} else {
-				outc <- val
+			} else if !send(ctx, outc, val) {
+				return
 			}
 		}
 	}
 }
+
+// mergeSelector reads values from a set of input channels for merge functions that
+// choose which input to read from.  Inputs are removed from the set when they are closed.
+type mergeSelector[T any] struct {
+	chans []<-chan T
+	// cases holds a receive case for each input, followed by the context's done
+	// channel and a timeout channel
+	cases []reflect.SelectCase
+	open  int
+}
+
+func newMergeSelector[T any](ctx context.Context, chans []<-chan T) *mergeSelector[T] {
+	selector := &mergeSelector[T]{
+		chans: make([]<-chan T, len(chans)),
+		cases: make([]reflect.SelectCase, len(chans)+2),
+		open:  len(chans),
+	}
+
+	copy(selector.chans, chans)
+	for i, c := range chans {
+		selector.cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)}
+	}
+	selector.cases[len(chans)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	selector.cases[len(chans)+1] = reflect.SelectCase{Dir: reflect.SelectRecv}
+
+	return selector
+}
+
+func (s *mergeSelector[T]) remove(i int) {
+	s.chans[i] = nil
+	s.cases[i].Chan = reflect.Value{}
+	s.open--
+}
+
+// pause stops receive from reading the input at index `i` until resume is called.
+func (s *mergeSelector[T]) pause(i int) {
+	s.cases[i].Chan = reflect.Value{}
+}
+
+func (s *mergeSelector[T]) resume(i int) {
+	if s.chans[i] != nil {
+		s.cases[i].Chan = reflect.ValueOf(s.chans[i])
+	}
+}
+
+// tryReceive reads a value from the input at index `i` without blocking.  Returns false
+// if the input doesn't have a value ready or is closed.
+func (s *mergeSelector[T]) tryReceive(i int) (T, bool) {
+	if s.chans[i] == nil {
+		return *new(T), false
+	}
+
+	select {
+	case val, ok := <-s.chans[i]:
+		if !ok {
+			s.remove(i)
+		}
+		return val, ok
+	default:
+		return *new(T), false
+	}
+}
+
+// receive blocks until a value is read from any open input, returning the value and
+// the index of its input.  Returns false when every input is closed or the context is done.
+func (s *mergeSelector[T]) receive() (T, int, bool) {
+	for s.open > 0 {
+		val, i, ok := s.receiveOrTimeout(nil)
+		if ok || i < 0 {
+			return val, i, ok
+		}
+	}
+
+	return *new(T), -1, false
+}
+
+// receiveOrTimeout blocks until a value is read from an input that isn't paused, one of
+// those inputs is closed, the context is done, or a value is read from the `timeout`
+// channel.  Returns the value and the index of its input when a value is read, the index
+// of a closed input with false, -1 with false when the context is done, and -1 with true
+// when the timeout channel is read.
+func (s *mergeSelector[T]) receiveOrTimeout(timeout <-chan time.Time) (T, int, bool) {
+	timeoutCase := len(s.chans) + 1
+	if timeout != nil {
+		s.cases[timeoutCase].Chan = reflect.ValueOf(timeout)
+		defer func() { s.cases[timeoutCase].Chan = reflect.Value{} }()
+	}
+
+	i, val, ok := reflect.Select(s.cases)
+	switch {
+	case i == len(s.chans):
+		return *new(T), -1, false
+	case i == timeoutCase:
+		return *new(T), -1, true
+	case !ok:
+		s.remove(i)
+		return *new(T), i, false
+	}
+
+	// a nil value of an interface type can't be asserted
+	out, _ := val.Interface().(T)
+	return out, i, true
+}
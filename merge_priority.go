@@ -0,0 +1,59 @@
+package channels
+
+// MergePriority merges multiple input channels into a single output channel,
+// always preferring values from earlier input channels.  When values are ready
+// in several input channels, the value from the input channel with the lowest
+// index is written first, so a busy input channel later in the list can't delay
+// values from earlier input channels.  Values from a single input channel are
+// written in order.  The output channel is unbuffered by default and is closed
+// when all input channels are closed.
+func MergePriority[T any](chans []<-chan T, opts ...Option[MergeConfig]) <-chan T {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan T, cfg.capacity)
+	operator := cfg.operator.or(MergePriorityOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+
+	selector := newMergeSelector(ctx, chans)
+	counts := make([]uint, len(chans))
+
+	// next reads the ready value from the earliest input, or waits for a value
+	// from any input when none are ready
+	next := func() (T, int, bool) {
+		for i := range chans {
+			if val, ok := selector.tryReceive(i); ok {
+				return val, i, true
+			}
+		}
+
+		return selector.receive()
+	}
+
+	for _, c := range chans {
+		drainOnStop(lifecycle, c)
+	}
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		for {
+			val, i, ok := next()
+			if !ok || !send(ctx, outc, val) {
+				return
+			}
+
+			counts[i]++
+			tryProvideStats(MergeStats{Operator: operator, Name: name, Input: i, Count: counts[i], QueueLength: len(chans[i])}, statsProvider)
+		}
+	}()
+
+	return outc
+}
@@ -0,0 +1,101 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePriority(t *testing.T) {
+	t.Parallel()
+
+	control := make(chan int, 3)
+	data := make(chan int, 3)
+	for i := 1; i <= 3; i++ {
+		data <- i * 10
+		control <- i
+	}
+	close(control)
+	close(data)
+
+	out := channels.MergePriority([]<-chan int{control, data})
+	require.Equal(t, 0, cap(out))
+
+	results := []int{}
+	for val := range out {
+		results = append(results, val)
+	}
+
+	require.Equal(t, []int{1, 2, 3, 10, 20, 30}, results)
+}
+
+func TestMergePriorityWaitsForAnyInput(t *testing.T) {
+	t.Parallel()
+
+	control := make(chan int)
+	data := make(chan int)
+	defer close(control)
+
+	out := channels.MergePriority([]<-chan int{control, data})
+
+	data <- 10
+	require.Equal(t, 10, <-out)
+	close(data)
+
+	control <- 1
+	require.Equal(t, 1, <-out)
+}
+
+func TestMergePriorityStatsProviderOption(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.MergeStats](10)
+	defer provider.Close()
+
+	control := make(chan int, 1)
+	data := make(chan int, 2)
+	control <- 1
+	data <- 10
+	data <- 20
+	close(control)
+	close(data)
+
+	out := channels.MergePriority([]<-chan int{control, data},
+		channels.MergeStatsProviderOption(provider),
+	)
+
+	for range out {
+	}
+
+	stats := <-receiver.Channel()
+	require.Equal(t, channels.MergePriorityOperator, stats.Operator)
+	require.Equal(t, 0, stats.Input)
+	require.Equal(t, uint(1), stats.Count)
+
+	stats = <-receiver.Channel()
+	require.Equal(t, 1, stats.Input)
+	require.Equal(t, uint(1), stats.Count)
+	require.Equal(t, 1, stats.QueueLength)
+
+	stats = <-receiver.Channel()
+	require.Equal(t, 1, stats.Input)
+	require.Equal(t, uint(2), stats.Count)
+	require.Equal(t, 0, stats.QueueLength)
+}
+
+func TestMergePriorityContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.MergePriority([]<-chan int{in}, channels.ContextOption[channels.MergeConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
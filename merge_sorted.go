@@ -0,0 +1,154 @@
+package channels
+
+import (
+	"time"
+
+	"github.com/jonabc/channels/clock"
+)
+
+// MergeSorted merges multiple input channels that are each sorted by `less`
+// into a single sorted output channel.  MergeSorted waits until it has read a
+// value from every open input channel, then writes the least value to the
+// output channel and reads the next value from that value's input channel.
+// When equal values are read from several input channels, the value from the
+// input channel with the lowest index is written first.  By default MergeSorted
+// waits indefinitely for a stalled input channel, see MergeIdleTimeoutOption to
+// continue without it until it has a value ready.  The output channel is unbuffered by default and is
+// closed when all input channels are closed and their values have been written.
+func MergeSorted[T any](chans []<-chan T, less func(T, T) bool, opts ...Option[MergeConfig]) <-chan T {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan T, cfg.capacity)
+	operator := cfg.operator.or(MergeSortedOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	idleTimeout := cfg.idleTimeout
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	selector := newMergeSelector(ctx, chans)
+	counts := make([]uint, len(chans))
+
+	// the next value read from each input, which is held until it's the least value
+	heads := make([]T, len(chans))
+	hasHead := make([]bool, len(chans))
+
+	// inputs that didn't have a value ready when the idle timeout elapsed aren't
+	// waited for until they have a value ready
+	stalled := make([]bool, len(chans))
+
+	// waiting returns whether an open input that isn't stalled doesn't have a held value
+	waiting := func() bool {
+		for i := range chans {
+			if selector.chans[i] != nil && !hasHead[i] && !stalled[i] {
+				return true
+			}
+		}
+		return false
+	}
+
+	hold := func(i int, val T) {
+		heads[i] = val
+		hasHead[i] = true
+		stalled[i] = false
+		selector.pause(i)
+	}
+
+	// least returns the index of the least held value, or -1 if no values are held
+	least := func() int {
+		index := -1
+		for i := range chans {
+			if hasHead[i] && (index < 0 || less(heads[i], heads[index])) {
+				index = i
+			}
+		}
+		return index
+	}
+
+	// fill reads values from open inputs until every open input has a held value, or
+	// until the idle timeout elapses while waiting with at least one held value.
+	// Returns false if the context is done.
+	fill := func() bool {
+		var timer clock.Timer
+		var timeout <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for i := range chans {
+			if stalled[i] {
+				if val, ok := selector.tryReceive(i); ok {
+					hold(i, val)
+				}
+			}
+		}
+
+		for {
+			if !waiting() {
+				if least() >= 0 || selector.open == 0 {
+					return true
+				}
+
+				// without any held values there's nothing to continue with, so wait for the stalled inputs
+				clear(stalled)
+			}
+
+			if idleTimeout > 0 && timer == nil && least() >= 0 {
+				timer = clk.NewTimer(idleTimeout)
+				timeout = timer.C()
+			}
+
+			val, i, ok := selector.receiveOrTimeout(timeout)
+			switch {
+			case i < 0 && !ok:
+				return false
+			case i < 0:
+				// the idle timeout elapsed, continue without the inputs that didn't have a value ready
+				for i := range chans {
+					stalled[i] = selector.chans[i] != nil && !hasHead[i]
+				}
+				return true
+			case !ok:
+				continue
+			}
+
+			hold(i, val)
+		}
+	}
+
+	for _, c := range chans {
+		drainOnStop(lifecycle, c)
+	}
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		for {
+			if !fill() {
+				return
+			}
+
+			i := least()
+			if i < 0 || !send(ctx, outc, heads[i]) {
+				return
+			}
+
+			heads[i] = *new(T)
+			hasHead[i] = false
+			selector.resume(i)
+
+			counts[i]++
+			tryProvideStats(MergeStats{Operator: operator, Name: name, Input: i, Count: counts[i], QueueLength: len(chans[i])}, statsProvider)
+		}
+	}()
+
+	return outc
+}
@@ -0,0 +1,151 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSorted(t *testing.T) {
+	t.Parallel()
+
+	inputs := [][]int{
+		{1, 4, 7, 10},
+		{2, 5},
+		{},
+		{0, 3, 6, 8, 9},
+	}
+
+	chans := make([]<-chan int, 0, len(inputs))
+	for _, values := range inputs {
+		c := make(chan int, len(values))
+		for _, val := range values {
+			c <- val
+		}
+		close(c)
+		chans = append(chans, c)
+	}
+
+	out := channels.MergeSorted(chans, func(a, b int) bool { return a < b })
+	require.Equal(t, 0, cap(out))
+
+	results := []int{}
+	for val := range out {
+		results = append(results, val)
+	}
+
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, results)
+}
+
+func TestMergeSortedWaitsForEveryInput(t *testing.T) {
+	t.Parallel()
+
+	fast := make(chan int, 2)
+	fast <- 1
+	fast <- 5
+	close(fast)
+
+	slow := make(chan int)
+	out := channels.MergeSorted([]<-chan int{fast, slow}, func(a, b int) bool { return a < b })
+
+	select {
+	case val := <-out:
+		require.Fail(t, "unexpected value before every input had a value", val)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	slow <- 2
+	require.Equal(t, 1, <-out)
+	require.Equal(t, 2, <-out)
+
+	slow <- 3
+	require.Equal(t, 3, <-out)
+	close(slow)
+
+	require.Equal(t, 5, <-out)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestMergeSortedIdleTimeoutOption(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+
+	fast := make(chan int, 2)
+	fast <- 1
+	fast <- 2
+	close(fast)
+
+	stalled := make(chan int)
+	out := channels.MergeSorted([]<-chan int{fast, stalled}, func(a, b int) bool { return a < b },
+		channels.ClockOption[channels.MergeConfig](clk),
+		channels.MergeIdleTimeoutOption(time.Second),
+	)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	require.Equal(t, 1, <-out)
+	// the stalled input isn't waited for again until it has a value ready
+	require.Equal(t, 2, <-out)
+
+	// values from a stalled input can be written out of order
+	stalled <- 0
+	require.Equal(t, 0, <-out)
+	close(stalled)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestMergeSortedStatsProviderOption(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.MergeStats](10)
+	defer provider.Close()
+
+	first := make(chan int, 1)
+	second := make(chan int, 1)
+	first <- 2
+	second <- 1
+	close(first)
+	close(second)
+
+	out := channels.MergeSorted([]<-chan int{first, second}, func(a, b int) bool { return a < b },
+		channels.MergeStatsProviderOption(provider),
+	)
+
+	for range out {
+	}
+
+	stats := <-receiver.Channel()
+	require.Equal(t, channels.MergeSortedOperator, stats.Operator)
+	require.Equal(t, 1, stats.Input)
+	require.Equal(t, uint(1), stats.Count)
+
+	stats = <-receiver.Channel()
+	require.Equal(t, 0, stats.Input)
+	require.Equal(t, uint(1), stats.Count)
+}
+
+func TestMergeSortedContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.MergeSorted([]<-chan int{in}, func(a, b int) bool { return a < b },
+		channels.ContextOption[channels.MergeConfig](ctx),
+	)
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
@@ -1,10 +1,12 @@
 package channels_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
 
@@ -77,3 +79,98 @@ func TestMergeChannelCapacityOption(t *testing.T) {
 
 	require.Equal(t, 5, cap(out))
 }
+
+func TestMergeContextOption(t *testing.T) {
+	t.Parallel()
+
+	chans := make([]chan int, 5)
+	readChans := make([]<-chan int, len(chans))
+	for i := range chans {
+		chans[i] = make(chan int, 1)
+		readChans[i] = chans[i]
+		defer close(chans[i])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.Merge(readChans, channels.ContextOption[channels.MergeConfig](ctx))
+
+	chans[0] <- 1
+	require.Equal(t, 1, <-out)
+	cancel()
+
+	for range out {
+		// a value may have been in flight when the context was cancelled
+	}
+}
+
+func TestMergeLifecycleProviderOption(t *testing.T) {
+	t.Parallel()
+
+	chans := make([]chan int, 5)
+	readChans := make([]<-chan int, len(chans))
+	for i := range chans {
+		chans[i] = make(chan int, 1)
+		readChans[i] = chans[i]
+	}
+
+	provider, receiver := providers.NewProvider[channels.LifecycleEvent](2)
+	defer provider.Close()
+
+	out := channels.Merge(readChans, channels.LifecycleProviderOption[channels.MergeConfig](provider))
+	require.Equal(t, channels.OperatorStarted, (<-receiver.Channel()).State)
+
+	chans[4] <- 1
+	require.Equal(t, 1, <-out)
+
+	for _, c := range chans {
+		close(c)
+	}
+
+	_, ok := <-out
+	require.False(t, ok)
+
+	stopped := <-receiver.Channel()
+	require.Equal(t, channels.MergeOperator, stopped.Operator)
+	require.Equal(t, channels.OperatorStopped, stopped.State)
+	require.NoError(t, stopped.Err)
+}
+
+func TestMergeLifecycleProviderOptionWithSingleInput(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 1)
+
+	provider, receiver := providers.NewProvider[channels.LifecycleEvent](2)
+	defer provider.Close()
+
+	out := channels.Merge([]<-chan int{in},
+		channels.NameOption[channels.MergeConfig]("merger"),
+		channels.LifecycleProviderOption[channels.MergeConfig](provider),
+	)
+
+	started := <-receiver.Channel()
+	require.Equal(t, channels.OperatorStarted, started.State)
+	require.Equal(t, "merger", started.Name)
+
+	in <- 1
+	require.Equal(t, 1, <-out)
+	close(in)
+
+	_, ok := <-out
+	require.False(t, ok)
+	require.Equal(t, channels.OperatorStopped, (<-receiver.Channel()).State)
+}
+
+func TestMergeContextOptionWithOneChannel(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.Merge([]<-chan int{in}, channels.ContextOption[channels.MergeConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
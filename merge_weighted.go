@@ -0,0 +1,81 @@
+package channels
+
+// MergeWeighted merges multiple input channels into a single output channel,
+// sharing the output channel between input channels by weighted round-robin.
+// In each round, up to `weights[i]` ready values are read from the input
+// channel at index `i` before moving on to the next input channel, so that
+// when every input channel is busy, each input channel's share of the output
+// is proportional to its weight.  Weights less than 1 are treated as 1.
+// Values from a single input channel are written in order.  The output channel
+// is unbuffered by default and is closed when all input channels are closed.
+// MergeWeighted panics if `weights` and `chans` have different lengths.
+func MergeWeighted[T any](chans []<-chan T, weights []int, opts ...Option[MergeConfig]) <-chan T {
+	if len(weights) != len(chans) {
+		panic("mismatched weights and channels for weighted merge")
+	}
+
+	weights = append([]int(nil), weights...)
+	cfg := parseOpts(opts...)
+
+	outc := make(chan T, cfg.capacity)
+	operator := cfg.operator.or(MergeWeightedOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+
+	selector := newMergeSelector(ctx, chans)
+	counts := make([]uint, len(chans))
+
+	emit := func(val T, i int) bool {
+		if !send(ctx, outc, val) {
+			return false
+		}
+
+		counts[i]++
+		tryProvideStats(MergeStats{Operator: operator, Name: name, Input: i, Count: counts[i], QueueLength: len(chans[i])}, statsProvider)
+		return true
+	}
+
+	for _, c := range chans {
+		drainOnStop(lifecycle, c)
+	}
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		for {
+			progressed := false
+			for i := range chans {
+				for n := 0; n < max(weights[i], 1); n++ {
+					val, ok := selector.tryReceive(i)
+					if !ok {
+						break
+					}
+
+					if !emit(val, i) {
+						return
+					}
+					progressed = true
+				}
+			}
+
+			if progressed {
+				continue
+			}
+
+			// no input had a value ready, wait for the next value from any input
+			val, i, ok := selector.receive()
+			if !ok || !emit(val, i) {
+				return
+			}
+		}
+	}()
+
+	return outc
+}
@@ -0,0 +1,110 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeWeighted(t *testing.T) {
+	t.Parallel()
+
+	heavy := make(chan int, 6)
+	light := make(chan int, 6)
+	for i := 1; i <= 6; i++ {
+		heavy <- i
+		light <- i * 10
+	}
+	close(heavy)
+	close(light)
+
+	out := channels.MergeWeighted([]<-chan int{heavy, light}, []int{2, 1})
+	require.Equal(t, 0, cap(out))
+
+	results := []int{}
+	for val := range out {
+		results = append(results, val)
+	}
+
+	require.Equal(t, []int{1, 2, 10, 3, 4, 20, 5, 6, 30, 40, 50, 60}, results)
+}
+
+func TestMergeWeightedWaitsForAnyInput(t *testing.T) {
+	t.Parallel()
+
+	first := make(chan int)
+	second := make(chan int)
+	defer close(first)
+
+	out := channels.MergeWeighted([]<-chan int{first, second}, []int{1, 1})
+
+	second <- 10
+	require.Equal(t, 10, <-out)
+	close(second)
+
+	first <- 1
+	require.Equal(t, 1, <-out)
+}
+
+func TestMergeWeightedPanicsWithMismatchedWeights(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	require.Panics(t, func() {
+		channels.MergeWeighted([]<-chan int{in}, []int{1, 2})
+	})
+}
+
+func TestMergeWeightedStatsProviderOption(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.MergeStats](10)
+	defer provider.Close()
+
+	first := make(chan int, 2)
+	second := make(chan int, 1)
+	first <- 1
+	first <- 2
+	second <- 10
+	close(first)
+	close(second)
+
+	out := channels.MergeWeighted([]<-chan int{first, second}, []int{1, 1},
+		channels.MergeStatsProviderOption(provider),
+	)
+
+	for range out {
+	}
+
+	stats := <-receiver.Channel()
+	require.Equal(t, channels.MergeWeightedOperator, stats.Operator)
+	require.Equal(t, 0, stats.Input)
+	require.Equal(t, uint(1), stats.Count)
+
+	stats = <-receiver.Channel()
+	require.Equal(t, 1, stats.Input)
+	require.Equal(t, uint(1), stats.Count)
+
+	stats = <-receiver.Channel()
+	require.Equal(t, 0, stats.Input)
+	require.Equal(t, uint(2), stats.Count)
+}
+
+func TestMergeWeightedContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.MergeWeighted([]<-chan int{in}, []int{1}, channels.ContextOption[channels.MergeConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package channels
+
+// OperatorKind identifies the channels function that produced a report.
+type OperatorKind string
+
+const (
+	BatchOperator           OperatorKind = "Batch"
+	BroadcastOperator       OperatorKind = "Broadcast"
+	CircuitBreakerOperator  OperatorKind = "CircuitBreaker"
+	CombineLatestOperator   OperatorKind = "CombineLatest"
+	DebounceOperator        OperatorKind = "Debounce"
+	DebounceCustomOperator  OperatorKind = "DebounceCustom"
+	DebounceValuesOperator  OperatorKind = "DebounceValues"
+	DelayOperator           OperatorKind = "Delay"
+	DelayCustomOperator     OperatorKind = "DelayCustom"
+	EachOperator            OperatorKind = "Each"
+	EachErrOperator         OperatorKind = "EachErr"
+	FlatMapOperator         OperatorKind = "FlatMap"
+	FlatMapErrOperator      OperatorKind = "FlatMapErr"
+	GroupByOperator         OperatorKind = "GroupBy"
+	MapOperator             OperatorKind = "Map"
+	MapErrOperator          OperatorKind = "MapErr"
+	MergeOperator           OperatorKind = "Merge"
+	MergePriorityOperator   OperatorKind = "MergePriority"
+	MergeSortedOperator     OperatorKind = "MergeSorted"
+	MergeWeightedOperator   OperatorKind = "MergeWeighted"
+	RateLimitOperator       OperatorKind = "RateLimit"
+	ReduceOperator          OperatorKind = "Reduce"
+	RejectOperator          OperatorKind = "Reject"
+	RetryOperator           OperatorKind = "Retry"
+	SelectOperator          OperatorKind = "Select"
+	SelectErrOperator       OperatorKind = "SelectErr"
+	SplitOperator           OperatorKind = "Split"
+	SlidingWindowOperator   OperatorKind = "SlidingWindow"
+	StatsAggregatorOperator OperatorKind = "StatsAggregator"
+	TapOperator             OperatorKind = "Tap"
+	ThrottleOperator        OperatorKind = "Throttle"
+	ThrottleCustomOperator  OperatorKind = "ThrottleCustom"
+	ThrottleValuesOperator  OperatorKind = "ThrottleValues"
+	TumblingWindowOperator  OperatorKind = "TumblingWindow"
+	UniqueOperator          OperatorKind = "Unique"
+	UniqueKeyedOperator     OperatorKind = "UniqueKeyed"
+	WithLatestFromOperator  OperatorKind = "WithLatestFrom"
+	ZipOperator             OperatorKind = "Zip"
+)
+
+// or returns the operator kind, or `operator` if the kind isn't set.
+func (k OperatorKind) or(operator OperatorKind) OperatorKind {
+	if k == "" {
+		return operator
+	}
+
+	return k
+}
+
+type operatorConfiguration interface {
+	BatchConfig |
+		DebounceConfig |
+		DelayConfig |
+		EachConfig |
+		FlatMapConfig |
+		MapConfig |
+		MergeConfig |
+		ReduceConfig |
+		SelectConfig |
+		SplitConfig |
+		TapConfig
+}
+
+// withOperator prepends an option setting the operator kind reported by a channels function
+// that wraps another channels function.  Options are applied in order, so when wrapped
+// functions are nested the outermost function's kind is reported.
+func withOperator[T operatorConfiguration](operator OperatorKind, opts []Option[T]) []Option[T] {
+	return append([]Option[T]{func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.operator = operator
+		case *DebounceConfig:
+			cfg.operator = operator
+		case *DelayConfig:
+			cfg.operator = operator
+		case *EachConfig:
+			cfg.operator = operator
+		case *FlatMapConfig:
+			cfg.operator = operator
+		case *MapConfig:
+			cfg.operator = operator
+		case *MergeConfig:
+			cfg.operator = operator
+		case *ReduceConfig:
+			cfg.operator = operator
+		case *SelectConfig:
+			cfg.operator = operator
+		case *SplitConfig:
+			cfg.operator = operator
+		case *TapConfig:
+			cfg.operator = operator
+		}
+	}}, opts...)
+}
@@ -1,18 +1,29 @@
 package channels
 
 import (
+	"context"
+	"time"
+
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type channelConfiguration interface {
 	BatchConfig |
+		BroadcastConfig |
+		CircuitBreakerConfig |
+		CombineConfig |
 		DebounceConfig |
 		DelayConfig |
+		DrainConfig |
 		EachConfig |
 		FlatMapConfig |
+		GroupByConfig |
 		MapConfig |
 		MergeConfig |
+		RateLimitConfig |
 		ReduceConfig |
+		RetryConfig |
 		SelectConfig |
 		SignalConfig |
 		SplitConfig |
@@ -26,6 +37,12 @@ func PanicProviderOption[T channelConfiguration](provider providers.Provider[any
 		switch cfg := any(cfg).(type) {
 		case *BatchConfig:
 			cfg.panicProvider = provider
+		case *BroadcastConfig:
+			cfg.panicProvider = provider
+		case *CircuitBreakerConfig:
+			cfg.panicProvider = provider
+		case *CombineConfig:
+			cfg.panicProvider = provider
 		case *DebounceConfig:
 			cfg.panicProvider = provider
 		case *DelayConfig:
@@ -34,12 +51,18 @@ func PanicProviderOption[T channelConfiguration](provider providers.Provider[any
 			cfg.panicProvider = provider
 		case *FlatMapConfig:
 			cfg.panicProvider = provider
+		case *GroupByConfig:
+			cfg.panicProvider = provider
 		case *MapConfig:
 			cfg.panicProvider = provider
 		case *MergeConfig:
 			cfg.panicProvider = provider
+		case *RateLimitConfig:
+			cfg.panicProvider = provider
 		case *ReduceConfig:
 			cfg.panicProvider = provider
+		case *RetryConfig:
+			cfg.panicProvider = provider
 		case *SelectConfig:
 			cfg.panicProvider = provider
 		case *SplitConfig:
@@ -50,14 +73,220 @@ func PanicProviderOption[T channelConfiguration](provider providers.Provider[any
 	}
 }
 
+// Specify a context for a channels function.  When the context is done the
+// function stops reading from its input channel, abandons any pending writes,
+// and closes its output channel(s).
+func ContextOption[T channelConfiguration](ctx context.Context) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.ctx = ctx
+		case *BroadcastConfig:
+			cfg.ctx = ctx
+		case *CircuitBreakerConfig:
+			cfg.ctx = ctx
+		case *CombineConfig:
+			cfg.ctx = ctx
+		case *DebounceConfig:
+			cfg.ctx = ctx
+		case *DelayConfig:
+			cfg.ctx = ctx
+		case *DrainConfig:
+			cfg.ctx = ctx
+		case *EachConfig:
+			cfg.ctx = ctx
+		case *FlatMapConfig:
+			cfg.ctx = ctx
+		case *GroupByConfig:
+			cfg.ctx = ctx
+		case *MapConfig:
+			cfg.ctx = ctx
+		case *MergeConfig:
+			cfg.ctx = ctx
+		case *RateLimitConfig:
+			cfg.ctx = ctx
+		case *ReduceConfig:
+			cfg.ctx = ctx
+		case *RetryConfig:
+			cfg.ctx = ctx
+		case *SelectConfig:
+			cfg.ctx = ctx
+		case *SignalConfig:
+			cfg.ctx = ctx
+		case *SplitConfig:
+			cfg.ctx = ctx
+		case *TapConfig:
+			cfg.ctx = ctx
+		}
+	}
+}
+
+// Specify a provider to receive the reason a channels function stopped early,
+// i.e. `context.Cause` of a context passed with `ContextOption` once it is done.
+func CancellationProviderOption[T channelConfiguration](provider providers.Provider[error]) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.cancellationProvider = provider
+		case *BroadcastConfig:
+			cfg.cancellationProvider = provider
+		case *CircuitBreakerConfig:
+			cfg.cancellationProvider = provider
+		case *CombineConfig:
+			cfg.cancellationProvider = provider
+		case *DebounceConfig:
+			cfg.cancellationProvider = provider
+		case *DelayConfig:
+			cfg.cancellationProvider = provider
+		case *DrainConfig:
+			cfg.cancellationProvider = provider
+		case *EachConfig:
+			cfg.cancellationProvider = provider
+		case *FlatMapConfig:
+			cfg.cancellationProvider = provider
+		case *GroupByConfig:
+			cfg.cancellationProvider = provider
+		case *MapConfig:
+			cfg.cancellationProvider = provider
+		case *MergeConfig:
+			cfg.cancellationProvider = provider
+		case *RateLimitConfig:
+			cfg.cancellationProvider = provider
+		case *ReduceConfig:
+			cfg.cancellationProvider = provider
+		case *RetryConfig:
+			cfg.cancellationProvider = provider
+		case *SelectConfig:
+			cfg.cancellationProvider = provider
+		case *SignalConfig:
+			cfg.cancellationProvider = provider
+		case *SplitConfig:
+			cfg.cancellationProvider = provider
+		case *TapConfig:
+			cfg.cancellationProvider = provider
+		}
+	}
+}
+
+// Specify a provider to receive a PanicInfo describing each panic recovered from a channels function.
+// PanicInfoProviderOption can be used alongside or instead of PanicProviderOption.
+func PanicInfoProviderOption[T channelConfiguration](provider providers.Provider[PanicInfo]) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.panicInfoProvider = provider
+		case *BroadcastConfig:
+			cfg.panicInfoProvider = provider
+		case *CircuitBreakerConfig:
+			cfg.panicInfoProvider = provider
+		case *CombineConfig:
+			cfg.panicInfoProvider = provider
+		case *DebounceConfig:
+			cfg.panicInfoProvider = provider
+		case *DelayConfig:
+			cfg.panicInfoProvider = provider
+		case *EachConfig:
+			cfg.panicInfoProvider = provider
+		case *FlatMapConfig:
+			cfg.panicInfoProvider = provider
+		case *GroupByConfig:
+			cfg.panicInfoProvider = provider
+		case *MapConfig:
+			cfg.panicInfoProvider = provider
+		case *MergeConfig:
+			cfg.panicInfoProvider = provider
+		case *RateLimitConfig:
+			cfg.panicInfoProvider = provider
+		case *ReduceConfig:
+			cfg.panicInfoProvider = provider
+		case *RetryConfig:
+			cfg.panicInfoProvider = provider
+		case *SelectConfig:
+			cfg.panicInfoProvider = provider
+		case *SplitConfig:
+			cfg.panicInfoProvider = provider
+		case *TapConfig:
+			cfg.panicInfoProvider = provider
+		}
+	}
+}
+
+type namedConfiguration interface {
+	BatchConfig |
+		BroadcastConfig |
+		CircuitBreakerConfig |
+		CombineConfig |
+		DebounceConfig |
+		DelayConfig |
+		EachConfig |
+		FlatMapConfig |
+		GroupByConfig |
+		MapConfig |
+		MergeConfig |
+		RateLimitConfig |
+		ReduceConfig |
+		RetryConfig |
+		SelectConfig |
+		SplitConfig |
+		TapConfig
+}
+
+// Specify a name for a channels function.  The name is included in stats, panic
+// and failure reports to identify the function within a pipeline.
+func NameOption[T namedConfiguration](name string) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.name = name
+		case *BroadcastConfig:
+			cfg.name = name
+		case *CircuitBreakerConfig:
+			cfg.name = name
+		case *CombineConfig:
+			cfg.name = name
+		case *DebounceConfig:
+			cfg.name = name
+		case *DelayConfig:
+			cfg.name = name
+		case *EachConfig:
+			cfg.name = name
+		case *FlatMapConfig:
+			cfg.name = name
+		case *GroupByConfig:
+			cfg.name = name
+		case *MapConfig:
+			cfg.name = name
+		case *MergeConfig:
+			cfg.name = name
+		case *RateLimitConfig:
+			cfg.name = name
+		case *ReduceConfig:
+			cfg.name = name
+		case *RetryConfig:
+			cfg.name = name
+		case *SelectConfig:
+			cfg.name = name
+		case *SplitConfig:
+			cfg.name = name
+		case *TapConfig:
+			cfg.name = name
+		}
+	}
+}
+
 type singleOutputConfiguration interface {
 	BatchConfig |
+		CircuitBreakerConfig |
+		CombineConfig |
 		DebounceConfig |
 		DelayConfig |
 		FlatMapConfig |
+		GroupByConfig |
 		MapConfig |
 		MergeConfig |
+		RateLimitConfig |
 		ReduceConfig |
+		RetryConfig |
 		SelectConfig |
 		SignalConfig |
 		TapConfig
@@ -69,18 +298,28 @@ func ChannelCapacityOption[T singleOutputConfiguration](capacity int) Option[T]
 		switch cfg := any(cfg).(type) {
 		case *BatchConfig:
 			cfg.capacity = capacity
+		case *CircuitBreakerConfig:
+			cfg.capacity = capacity
+		case *CombineConfig:
+			cfg.capacity = capacity
 		case *DebounceConfig:
 			cfg.capacity = capacity
 		case *DelayConfig:
 			cfg.capacity = capacity
 		case *FlatMapConfig:
 			cfg.capacity = capacity
+		case *GroupByConfig:
+			cfg.capacity = capacity
 		case *MapConfig:
 			cfg.capacity = capacity
 		case *MergeConfig:
 			cfg.capacity = capacity
+		case *RateLimitConfig:
+			cfg.capacity = capacity
 		case *ReduceConfig:
 			cfg.capacity = capacity
+		case *RetryConfig:
+			cfg.capacity = capacity
 		case *SignalConfig:
 			cfg.capacity = capacity
 		case *SelectConfig:
@@ -92,13 +331,16 @@ func ChannelCapacityOption[T singleOutputConfiguration](capacity int) Option[T]
 }
 
 type multiOutputConfiguration interface {
-	SplitConfig
+	BroadcastConfig |
+		SplitConfig
 }
 
 // Specify the capacities for output channels created from functions which return multiple channels.
 func MultiChannelCapacitiesOption[T multiOutputConfiguration](capacities []int) Option[T] {
 	return func(cfg *T) {
 		switch cfg := any(cfg).(type) {
+		case *BroadcastConfig:
+			cfg.capacities = capacities
 		case *SplitConfig:
 			cfg.capacities = capacities
 		}
@@ -115,7 +357,8 @@ func parseOpts[C any, O ~func(*C)](opts ...O) *C {
 }
 
 type statsConfiguration interface {
-	DelayConfig |
+	CircuitBreakerConfig |
+		DelayConfig |
 		EachConfig |
 		FlatMapConfig |
 		MapConfig |
@@ -127,6 +370,8 @@ type statsConfiguration interface {
 func StatsProviderOption[T statsConfiguration](provider providers.Provider[Stats]) Option[T] {
 	return func(cfg *T) {
 		switch cfg := any(cfg).(type) {
+		case *CircuitBreakerConfig:
+			cfg.statsProvider = provider
 		case *DelayConfig:
 			cfg.statsProvider = provider
 		case *EachConfig:
@@ -143,6 +388,305 @@ func StatsProviderOption[T statsConfiguration](provider providers.Provider[Stats
 	}
 }
 
+type concurrencyConfiguration interface {
+	EachConfig |
+		FlatMapConfig |
+		MapConfig |
+		SelectConfig
+}
+
+// Specify the number of workers processing values read from the input channel,
+// and whether results are written to the output channel in input order or as
+// soon as they are ready.  Values are processed by a single worker by default.
+func ConcurrencyOption[T concurrencyConfiguration](workers int, order OutputOrder) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *EachConfig:
+			cfg.workers = workers
+			cfg.order = order
+		case *FlatMapConfig:
+			cfg.workers = workers
+			cfg.order = order
+		case *MapConfig:
+			cfg.workers = workers
+			cfg.order = order
+		case *SelectConfig:
+			cfg.workers = workers
+			cfg.order = order
+		}
+	}
+}
+
+type errorConfiguration interface {
+	CircuitBreakerConfig |
+		EachConfig |
+		FlatMapConfig |
+		GroupByConfig |
+		MapConfig |
+		RateLimitConfig |
+		RetryConfig |
+		SelectConfig
+}
+
+// Specify a provider to receive values that failed processing in the error returning
+// variants of channels functions, e.g. MapErr, along with values that failed or were
+// rejected by CircuitBreaker, dropped by GroupBy or RateLimit, or exhausted their
+// attempts in Retry.
+//
+// The provider receives failures for the function's input type, e.g. a
+// providers.Provider[Failure[int]] for MapErr reading from a chan int.  A
+// providers.Provider[Failure[any]] can be used with any function, and receives
+// failures with the input value stored as an any.  The function panics when it's
+// created with a provider for a different type.
+func ErrorProviderOption[T errorConfiguration, TIn any](provider providers.Provider[Failure[TIn]]) Option[T] {
+	return func(cfg *T) {
+		// a nil provider is stored as a nil any, so that it's treated as unset
+		var errorProvider any
+		if provider != nil {
+			errorProvider = provider
+		}
+
+		switch cfg := any(cfg).(type) {
+		case *CircuitBreakerConfig:
+			cfg.errorProvider = errorProvider
+		case *EachConfig:
+			cfg.errorProvider = errorProvider
+		case *FlatMapConfig:
+			cfg.errorProvider = errorProvider
+		case *GroupByConfig:
+			cfg.errorProvider = errorProvider
+		case *MapConfig:
+			cfg.errorProvider = errorProvider
+		case *RateLimitConfig:
+			cfg.errorProvider = errorProvider
+		case *RetryConfig:
+			cfg.errorProvider = errorProvider
+		case *SelectConfig:
+			cfg.errorProvider = errorProvider
+		}
+	}
+}
+
+type clockConfiguration interface {
+	BatchConfig |
+		BroadcastConfig |
+		CircuitBreakerConfig |
+		CombineConfig |
+		DebounceConfig |
+		DelayConfig |
+		DrainConfig |
+		EachConfig |
+		FlatMapConfig |
+		GroupByConfig |
+		MapConfig |
+		MergeConfig |
+		RateLimitConfig |
+		ReduceConfig |
+		RetryConfig |
+		SelectConfig |
+		SplitConfig |
+		TapConfig
+}
+
+// Specify the clock used by time based channels functions, and to measure durations
+// and timestamp the values reported to providers.  The default clock is backed by the
+// time package, see the clock package for a manually advanced clock suitable for tests.
+func ClockOption[T clockConfiguration](c clock.Clock) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.clock = c
+		case *BroadcastConfig:
+			cfg.clock = c
+		case *CircuitBreakerConfig:
+			cfg.clock = c
+		case *CombineConfig:
+			cfg.clock = c
+		case *DebounceConfig:
+			cfg.clock = c
+		case *DelayConfig:
+			cfg.clock = c
+		case *DrainConfig:
+			cfg.clock = c
+		case *EachConfig:
+			cfg.clock = c
+		case *FlatMapConfig:
+			cfg.clock = c
+		case *GroupByConfig:
+			cfg.clock = c
+		case *MapConfig:
+			cfg.clock = c
+		case *MergeConfig:
+			cfg.clock = c
+		case *RateLimitConfig:
+			cfg.clock = c
+		case *ReduceConfig:
+			cfg.clock = c
+		case *RetryConfig:
+			cfg.clock = c
+		case *SelectConfig:
+			cfg.clock = c
+		case *SplitConfig:
+			cfg.clock = c
+		case *TapConfig:
+			cfg.clock = c
+		}
+	}
+}
+
+type panicRecoveryConfiguration interface {
+	CircuitBreakerConfig |
+		DebounceConfig |
+		DelayConfig |
+		EachConfig |
+		FlatMapConfig |
+		MapConfig |
+		ReduceConfig |
+		RetryConfig |
+		SelectConfig |
+		SplitConfig |
+		TapConfig
+}
+
+// Specify how a channels function handles panics from user provided functions.  By default
+// a panic stops the function (StopOnPanic).  With ContinueOnPanic, a panic is recovered
+// while processing a single value and reported to the panic provider as a RecoveredPanic,
+// and the function continues processing the next value.
+func PanicRecoveryOption[T panicRecoveryConfiguration](recovery PanicRecovery) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *CircuitBreakerConfig:
+			cfg.panicRecovery = recovery
+		case *DebounceConfig:
+			cfg.panicRecovery = recovery
+		case *DelayConfig:
+			cfg.panicRecovery = recovery
+		case *EachConfig:
+			cfg.panicRecovery = recovery
+		case *FlatMapConfig:
+			cfg.panicRecovery = recovery
+		case *MapConfig:
+			cfg.panicRecovery = recovery
+		case *ReduceConfig:
+			cfg.panicRecovery = recovery
+		case *RetryConfig:
+			cfg.panicRecovery = recovery
+		case *SelectConfig:
+			cfg.panicRecovery = recovery
+		case *SplitConfig:
+			cfg.panicRecovery = recovery
+		case *TapConfig:
+			cfg.panicRecovery = recovery
+		}
+	}
+}
+
+type lifecycleConfiguration interface {
+	BatchConfig |
+		BroadcastConfig |
+		CircuitBreakerConfig |
+		CombineConfig |
+		DebounceConfig |
+		DelayConfig |
+		EachConfig |
+		FlatMapConfig |
+		GroupByConfig |
+		MapConfig |
+		MergeConfig |
+		RateLimitConfig |
+		ReduceConfig |
+		RetryConfig |
+		SelectConfig |
+		SplitConfig |
+		TapConfig
+}
+
+// Specify a provider to receive a LifecycleEvent when a channels function starts
+// processing values, and when it stops after closing its output channel(s).
+func LifecycleProviderOption[T lifecycleConfiguration](provider providers.Provider[LifecycleEvent]) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.lifecycleProvider = provider
+		case *BroadcastConfig:
+			cfg.lifecycleProvider = provider
+		case *CircuitBreakerConfig:
+			cfg.lifecycleProvider = provider
+		case *CombineConfig:
+			cfg.lifecycleProvider = provider
+		case *DebounceConfig:
+			cfg.lifecycleProvider = provider
+		case *DelayConfig:
+			cfg.lifecycleProvider = provider
+		case *EachConfig:
+			cfg.lifecycleProvider = provider
+		case *FlatMapConfig:
+			cfg.lifecycleProvider = provider
+		case *GroupByConfig:
+			cfg.lifecycleProvider = provider
+		case *MapConfig:
+			cfg.lifecycleProvider = provider
+		case *MergeConfig:
+			cfg.lifecycleProvider = provider
+		case *RateLimitConfig:
+			cfg.lifecycleProvider = provider
+		case *ReduceConfig:
+			cfg.lifecycleProvider = provider
+		case *RetryConfig:
+			cfg.lifecycleProvider = provider
+		case *SelectConfig:
+			cfg.lifecycleProvider = provider
+		case *SplitConfig:
+			cfg.lifecycleProvider = provider
+		case *TapConfig:
+			cfg.lifecycleProvider = provider
+		}
+	}
+}
+
+// Specify a handle to stop a channels function before its input channels are closed,
+// and to wait for the function to exit.  See StopHandle.
+func StopHandleOption[T lifecycleConfiguration](handle *StopHandle) Option[T] {
+	return func(cfg *T) {
+		switch cfg := any(cfg).(type) {
+		case *BatchConfig:
+			cfg.stopHandle = handle
+		case *BroadcastConfig:
+			cfg.stopHandle = handle
+		case *CircuitBreakerConfig:
+			cfg.stopHandle = handle
+		case *CombineConfig:
+			cfg.stopHandle = handle
+		case *DebounceConfig:
+			cfg.stopHandle = handle
+		case *DelayConfig:
+			cfg.stopHandle = handle
+		case *EachConfig:
+			cfg.stopHandle = handle
+		case *FlatMapConfig:
+			cfg.stopHandle = handle
+		case *GroupByConfig:
+			cfg.stopHandle = handle
+		case *MapConfig:
+			cfg.stopHandle = handle
+		case *MergeConfig:
+			cfg.stopHandle = handle
+		case *RateLimitConfig:
+			cfg.stopHandle = handle
+		case *ReduceConfig:
+			cfg.stopHandle = handle
+		case *RetryConfig:
+			cfg.stopHandle = handle
+		case *SelectConfig:
+			cfg.stopHandle = handle
+		case *SplitConfig:
+			cfg.stopHandle = handle
+		case *TapConfig:
+			cfg.stopHandle = handle
+		}
+	}
+}
+
 // Specify a stats provider to receive information about batch operations.
 func BatchStatsProviderOption(provider providers.Provider[BatchStats]) Option[BatchConfig] {
 	return func(cfg *BatchConfig) {
@@ -150,6 +694,45 @@ func BatchStatsProviderOption(provider providers.Provider[BatchStats]) Option[Ba
 	}
 }
 
+// Specify whether TumblingWindow and SlidingWindow write windows without values
+// to the output channel.  Empty windows are not written by default.
+func EmptyWindowsOption(emit bool) Option[BatchConfig] {
+	return func(cfg *BatchConfig) {
+		cfg.emitEmpty = emit
+	}
+}
+
+// Specify when CircuitBreaker opens and closes its circuit.  By default the circuit
+// opens after 5 failures within 10 seconds, and is half-open after 30 seconds.
+func CircuitPolicyOption(policy CircuitPolicy) Option[CircuitBreakerConfig] {
+	return func(cfg *CircuitBreakerConfig) {
+		cfg.policy = policy
+	}
+}
+
+// Specify a provider to receive changes to the state of CircuitBreaker's circuit.
+func CircuitStateProviderOption(provider providers.Provider[CircuitTransition]) Option[CircuitBreakerConfig] {
+	return func(cfg *CircuitBreakerConfig) {
+		cfg.stateProvider = provider
+	}
+}
+
+// Specify a stats provider to receive information about broadcast operations.
+func BroadcastStatsProviderOption(provider providers.Provider[BroadcastStats]) Option[BroadcastConfig] {
+	return func(cfg *BroadcastConfig) {
+		cfg.statsProvider = provider
+	}
+}
+
+// Specify how Broadcast writes values to each output channel.  The policy at index `i`
+// applies to the output channel at index `i`, and output channels without a policy
+// use BlockOnFullOutput.
+func BroadcastPoliciesOption(policies []BroadcastPolicy) Option[BroadcastConfig] {
+	return func(cfg *BroadcastConfig) {
+		cfg.policies = policies
+	}
+}
+
 // Specify a stats provider to receive information about debounce operations.
 func DebounceStatsProviderOption(provider providers.Provider[DebounceStats]) Option[DebounceConfig] {
 	return func(cfg *DebounceConfig) {
@@ -157,6 +740,89 @@ func DebounceStatsProviderOption(provider providers.Provider[DebounceStats]) Opt
 	}
 }
 
+// Specify the capacity of each group channel created by GroupBy.  Group channels are
+// unbuffered by default.
+func GroupCapacityOption(capacity int) Option[GroupByConfig] {
+	return func(cfg *GroupByConfig) {
+		cfg.groupCapacity = capacity
+	}
+}
+
+// Specify how long a group created by GroupBy stays open without receiving a value.
+// By default groups stay open until the input channel is closed.
+func GroupIdleTimeoutOption(timeout time.Duration) Option[GroupByConfig] {
+	return func(cfg *GroupByConfig) {
+		cfg.idleTimeout = timeout
+	}
+}
+
+// Specify the maximum number of open groups created by GroupBy, and what happens
+// when a value is read for a new group once the limit is reached.  By default the
+// number of open groups is unlimited.
+func GroupLimitOption(limit int, policy GroupLimitPolicy) Option[GroupByConfig] {
+	return func(cfg *GroupByConfig) {
+		cfg.limit = limit
+		cfg.limitPolicy = policy
+	}
+}
+
+// Specify a stats provider to receive information about the inputs that values
+// are read from by MergePriority, MergeSorted and MergeWeighted.
+func MergeStatsProviderOption(provider providers.Provider[MergeStats]) Option[MergeConfig] {
+	return func(cfg *MergeConfig) {
+		cfg.statsProvider = provider
+	}
+}
+
+// Specify how long MergeSorted waits for a value from a stalled input channel before
+// continuing with the values read from the other input channels.  MergeSorted waits
+// for the stalled input channel again once it has a value ready, and values read
+// from it afterwards may be written out of order.  By default MergeSorted waits
+// indefinitely.
+func MergeIdleTimeoutOption(timeout time.Duration) Option[MergeConfig] {
+	return func(cfg *MergeConfig) {
+		cfg.idleTimeout = timeout
+	}
+}
+
+// Specify a stats provider to receive information about rate limit operations.
+func RateLimitStatsProviderOption(provider providers.Provider[RateLimitStats]) Option[RateLimitConfig] {
+	return func(cfg *RateLimitConfig) {
+		cfg.statsProvider = provider
+	}
+}
+
+// Specify whether RateLimit waits for the rate limit or drops values that exceed it.
+func RateLimitModeOption(mode RateLimitMode) Option[RateLimitConfig] {
+	return func(cfg *RateLimitConfig) {
+		cfg.mode = mode
+	}
+}
+
+// Specify a stats provider to receive information about each attempt made by Retry.
+func RetryStatsProviderOption(provider providers.Provider[RetryStats]) Option[RetryConfig] {
+	return func(cfg *RetryConfig) {
+		cfg.statsProvider = provider
+	}
+}
+
+// Specify the delays between attempts made by Retry.  By default the first retry
+// waits 100ms, and each following retry waits twice as long, up to 10 seconds.
+func RetryBackoffOption(backoff Backoff) Option[RetryConfig] {
+	return func(cfg *RetryConfig) {
+		cfg.backoff = backoff
+	}
+}
+
+// Specify the maximum number of values waiting to be retried by Retry at once.  Once
+// the limit is reached, Retry stops reading from the input channel until a value
+// succeeds or exhausts its attempts.  By default up to 100 values wait to be retried.
+func RetryLimitOption(limit int) Option[RetryConfig] {
+	return func(cfg *RetryConfig) {
+		cfg.retryLimit = limit
+	}
+}
+
 // Specify a stats provider to receive information about select and reject operations.
 func SelectStatsProviderOption(provider providers.Provider[SelectStats]) Option[SelectConfig] {
 	return func(cfg *SelectConfig) {
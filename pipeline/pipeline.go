@@ -0,0 +1,280 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+)
+
+// ErrStopped is the error returned by Err when a pipeline is stopped with Stop.  It's
+// the same error as channels.ErrStopped, which is reported by the pipeline's stages.
+var ErrStopped = channels.ErrStopped
+
+// PanicError is the error returned by Err when a panic stops a stage of a pipeline.
+type PanicError struct {
+	// The value passed to panic
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pipeline stage panicked: %v", e.Value)
+}
+
+// Config contains user configurable defaults applied to every stage of a pipeline.
+type Config struct {
+	ctx                 context.Context
+	panicProvider       providers.Provider[any]
+	panicInfoProvider   providers.Provider[channels.PanicInfo]
+	errorProvider       providers.Provider[channels.Failure[any]]
+	statsProvider       providers.Provider[channels.Stats]
+	batchStatsProvider  providers.Provider[channels.BatchStats]
+	selectStatsProvider providers.Provider[channels.SelectStats]
+	tapStatsProvider    providers.Provider[channels.TapStats]
+	lifecycleProvider   providers.Provider[channels.LifecycleEvent]
+	capacity            int
+}
+
+type Option func(*Config)
+
+// Specify a context for the pipeline.  When the context is done every stage
+// of the pipeline is stopped.
+func ContextOption(ctx context.Context) Option {
+	return func(cfg *Config) {
+		cfg.ctx = ctx
+	}
+}
+
+// Specify a provider receiving panics from every stage of the pipeline.
+func PanicProviderOption(provider providers.Provider[any]) Option {
+	return func(cfg *Config) {
+		cfg.panicProvider = provider
+	}
+}
+
+// Specify a provider receiving structured panic reports from every stage of the pipeline.
+func PanicInfoProviderOption(provider providers.Provider[channels.PanicInfo]) Option {
+	return func(cfg *Config) {
+		cfg.panicInfoProvider = provider
+	}
+}
+
+// Specify a provider receiving failed values from every error returning stage of the pipeline.
+// Stages have different input types, so the provider receives failures with the input
+// value stored as an any.
+func ErrorProviderOption(provider providers.Provider[channels.Failure[any]]) Option {
+	return func(cfg *Config) {
+		cfg.errorProvider = provider
+	}
+}
+
+// Specify a provider receiving stats from the Each, FlatMap, Map and Reduce stages of the pipeline.
+func StatsProviderOption(provider providers.Provider[channels.Stats]) Option {
+	return func(cfg *Config) {
+		cfg.statsProvider = provider
+	}
+}
+
+// Specify a provider receiving stats from the Batch stages of the pipeline.
+func BatchStatsProviderOption(provider providers.Provider[channels.BatchStats]) Option {
+	return func(cfg *Config) {
+		cfg.batchStatsProvider = provider
+	}
+}
+
+// Specify a provider receiving stats from the Select and Reject stages of the pipeline.
+func SelectStatsProviderOption(provider providers.Provider[channels.SelectStats]) Option {
+	return func(cfg *Config) {
+		cfg.selectStatsProvider = provider
+	}
+}
+
+// Specify a provider receiving stats from the Tap stages of the pipeline.
+func TapStatsProviderOption(provider providers.Provider[channels.TapStats]) Option {
+	return func(cfg *Config) {
+		cfg.tapStatsProvider = provider
+	}
+}
+
+// Specify a provider receiving lifecycle events from every stage of the pipeline.
+func LifecycleProviderOption(provider providers.Provider[channels.LifecycleEvent]) Option {
+	return func(cfg *Config) {
+		cfg.lifecycleProvider = provider
+	}
+}
+
+// Specify the output channel capacity of every stage of the pipeline.
+func ChannelCapacityOption(capacity int) Option {
+	return func(cfg *Config) {
+		cfg.capacity = capacity
+	}
+}
+
+// Pipeline chains channels functions into stages that share a context and
+// a set of default options.  Values are not read from the pipeline's sources
+// until Run is called.
+type Pipeline struct {
+	cfg       *Config
+	ctx       context.Context
+	cancel    context.CancelCauseFunc
+	panics    providers.Provider[any]
+	lifecycle providers.Provider[channels.LifecycleEvent]
+
+	started  chan struct{}
+	done     chan struct{}
+	runOnce  sync.Once
+	watching sync.Once
+	mu       sync.Mutex
+	sealed   bool
+	stages   sync.WaitGroup
+	err      error
+}
+
+// New creates a pipeline.  Stages are added to the pipeline by passing it to From,
+// and passing the returned stage to the stage functions in this package.
+func New(opts ...Option) *Pipeline {
+	cfg := &Config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ctx := cfg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	p := &Pipeline{
+		cfg:     cfg,
+		ctx:     ctx,
+		cancel:  cancel,
+		started: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	p.panics = &panicForwarder{pipeline: p, provider: cfg.panicProvider}
+	p.lifecycle = &lifecycleForwarder{pipeline: p, provider: cfg.lifecycleProvider}
+
+	return p
+}
+
+// Run starts reading values from the pipeline's sources.  Calling Run more
+// than once has no effect.
+func (p *Pipeline) Run() {
+	p.runOnce.Do(func() {
+		p.watch()
+		close(p.started)
+	})
+}
+
+// Stop stops every stage of the pipeline.  Values that have not been read
+// from the pipeline's sources are left unread.
+func (p *Pipeline) Stop() {
+	p.cancel(channels.ErrStopped)
+	p.watch()
+}
+
+// Done returns a channel that is closed after Run or Stop is called and every
+// stage of the pipeline has exited and closed its output channel.  A stage whose
+// output is read with Channel exits once its input is closed and its output is
+// drained, or once the pipeline is stopped.
+func (p *Pipeline) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait blocks until the channel returned by Done is closed, and returns the
+// value of Err.
+func (p *Pipeline) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// Err returns nil until the channel returned by Done is closed.  Afterwards Err
+// returns nil if the pipeline completed, ErrStopped if the pipeline was stopped,
+// a *PanicError if a stage panicked, or the cause of the pipeline's context being done.
+func (p *Pipeline) Err() error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+		return nil
+	}
+}
+
+func (p *Pipeline) watch() {
+	p.watching.Do(func() {
+		p.mu.Lock()
+		p.sealed = true
+		p.mu.Unlock()
+
+		go func() {
+			p.stages.Wait()
+			p.err = context.Cause(p.ctx)
+			close(p.done)
+		}()
+	})
+}
+
+// addStage registers a stage that the pipeline waits on to exit.  Every call must
+// be matched by a call to p.stages.Done when the stage exits.
+func (p *Pipeline) addStage() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sealed {
+		panic("pipeline: stages can't be added after calling Run or Stop")
+	}
+
+	p.stages.Add(1)
+}
+
+// panicForwarder stops the pipeline when a panic stops one of its stages, and forwards
+// panics to the pipeline's panic provider.
+type panicForwarder struct {
+	pipeline *Pipeline
+	provider providers.Provider[any]
+}
+
+func (f *panicForwarder) IsClosed() bool {
+	return false
+}
+
+func (f *panicForwarder) Close() {}
+
+func (f *panicForwarder) Provide(val any) bool {
+	if _, recovered := val.(channels.RecoveredPanic); !recovered {
+		f.pipeline.cancel(&PanicError{Value: val})
+	}
+
+	if f.provider == nil {
+		return false
+	}
+
+	return f.provider.Provide(val)
+}
+
+// lifecycleForwarder marks a stage of the pipeline as exited when the stage reports
+// that it stopped, and forwards lifecycle events to the pipeline's lifecycle provider.
+type lifecycleForwarder struct {
+	pipeline *Pipeline
+	provider providers.Provider[channels.LifecycleEvent]
+}
+
+func (f *lifecycleForwarder) IsClosed() bool {
+	return false
+}
+
+func (f *lifecycleForwarder) Close() {}
+
+func (f *lifecycleForwarder) Provide(event channels.LifecycleEvent) bool {
+	if event.State == channels.OperatorStopped {
+		defer f.pipeline.stages.Done()
+	}
+
+	if f.provider == nil {
+		return false
+	}
+
+	return f.provider.Provide(event)
+}
@@ -0,0 +1,213 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/pipeline"
+	"github.com/jonabc/channels/providers"
+)
+
+func TestPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	p := pipeline.New()
+
+	var mu sync.Mutex
+	out := []int{}
+	pipeline.Each(pipeline.From(p, in), func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		out = append(out, i)
+	})
+
+	in <- 1
+	in <- 2
+	close(in)
+
+	// values aren't read until the pipeline is run
+	require.Len(t, in, 2)
+	select {
+	case <-p.Done():
+		require.Fail(t, "pipeline completed before running")
+	default:
+	}
+
+	p.Run()
+	require.NoError(t, p.Wait())
+	require.NoError(t, p.Err())
+	require.Equal(t, []int{1, 2}, out)
+}
+
+func TestPipelineStop(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	defer close(in)
+
+	p := pipeline.New()
+	processed := make(chan int)
+	pipeline.Each(pipeline.From(p, in), func(i int) { processed <- i })
+
+	p.Run()
+	in <- 1
+	require.Equal(t, 1, <-processed)
+	require.NoError(t, p.Err())
+
+	p.Stop()
+	err := p.Wait()
+	require.ErrorIs(t, err, pipeline.ErrStopped)
+	require.ErrorIs(t, err, channels.ErrStopped)
+}
+
+func TestPipelineStopBeforeRun(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	in <- 1
+
+	p := pipeline.New()
+	pipeline.Each(pipeline.From(p, in), func(i int) { require.Fail(t, "value processed") })
+
+	p.Stop()
+	require.ErrorIs(t, p.Wait(), pipeline.ErrStopped)
+	require.Len(t, in, 1)
+}
+
+func TestPipelineContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	defer close(in)
+
+	stopped := errors.New("stopped")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	p := pipeline.New(pipeline.ContextOption(ctx))
+	pipeline.Each(pipeline.From(p, in), func(i int) {})
+
+	p.Run()
+	cancel(stopped)
+	require.ErrorIs(t, p.Wait(), stopped)
+}
+
+func TestPipelinePanicProviderOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[any](1)
+	defer provider.Close()
+
+	p := pipeline.New(pipeline.PanicProviderOption(provider))
+	mapped := pipeline.Map(pipeline.From(p, in), func(i int) (int, bool) { panic("panic!") })
+	pipeline.Each(mapped, func(i int) {})
+
+	p.Run()
+	in <- 1
+
+	require.Equal(t, "panic!", <-receiver.Channel())
+	err := p.Wait()
+	var panicErr *pipeline.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "panic!", panicErr.Value)
+}
+
+func TestPipelineRecoveredPanicsDontStopPipeline(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+
+	provider, receiver := providers.NewProvider[any](1)
+	defer provider.Close()
+
+	p := pipeline.New(pipeline.PanicProviderOption(provider))
+	mapped := pipeline.Map(pipeline.From(p, in),
+		func(i int) (int, bool) {
+			if i == 1 {
+				panic("panic!")
+			}
+			return i, true
+		},
+		channels.PanicRecoveryOption[channels.MapConfig](channels.ContinueOnPanic),
+	)
+
+	out := make(chan int, 10)
+	pipeline.Each(mapped, func(i int) { out <- i })
+
+	p.Run()
+	in <- 1
+	in <- 2
+	close(in)
+
+	recovered := (<-receiver.Channel()).(channels.RecoveredPanic)
+	require.Equal(t, 1, recovered.Input)
+	require.NoError(t, p.Wait())
+	require.Equal(t, 2, <-out)
+}
+
+func TestPipelineWithoutEachStages(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+
+	p := pipeline.New()
+	out := pipeline.Map(pipeline.From(p, in), func(i int) (int, bool) { return i * 2, true })
+
+	p.Run()
+	in <- 1
+	require.Equal(t, 2, <-out.Channel())
+
+	select {
+	case <-p.Done():
+		require.Fail(t, "pipeline completed while reading its input")
+	default:
+	}
+
+	// the pipeline completes once the Map stage exits
+	close(in)
+	require.NoError(t, p.Wait())
+	_, ok := <-out.Channel()
+	require.False(t, ok)
+}
+
+func TestPipelineLifecycleProviderOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	defer close(in)
+
+	provider, receiver := providers.NewCollectingProvider[channels.LifecycleEvent](0)
+	defer provider.Close()
+
+	p := pipeline.New(pipeline.LifecycleProviderOption(provider))
+	mapped := pipeline.Map(pipeline.From(p, in), func(i int) (int, bool) { return i, true })
+	selected := pipeline.Select(mapped, func(i int) bool { return true })
+	pipeline.Each(selected, func(i int) {})
+
+	p.Run()
+	in <- 1
+	p.Stop()
+	require.ErrorIs(t, p.Wait(), pipeline.ErrStopped)
+
+	// every stage has exited once the pipeline is done
+	stopped := map[channels.OperatorKind]error{}
+	for len(stopped) < 3 {
+		for _, event := range <-receiver.Channel() {
+			if event.State == channels.OperatorStopped {
+				stopped[event.Operator] = event.Err
+			}
+		}
+	}
+	require.Equal(t, map[channels.OperatorKind]error{
+		channels.MapOperator:    pipeline.ErrStopped,
+		channels.SelectOperator: pipeline.ErrStopped,
+		channels.EachOperator:   pipeline.ErrStopped,
+	}, stopped)
+}
@@ -0,0 +1,233 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/jonabc/channels"
+)
+
+// Stage is a step of a pipeline producing values of type T.  Each stage's
+// output should be used as the input to a single downstream stage.
+type Stage[T any] struct {
+	pipeline *Pipeline
+	outc     <-chan T
+}
+
+// Channel returns the stage's output channel, for reading values from the
+// pipeline outside of an Each stage.  The pipeline doesn't wait for values
+// read from the channel to be processed before completing.
+func (s *Stage[T]) Channel() <-chan T {
+	return s.outc
+}
+
+// Pipeline returns the pipeline that the stage belongs to.
+func (s *Stage[T]) Pipeline() *Pipeline {
+	return s.pipeline
+}
+
+type stageConfiguration interface {
+	channels.BatchConfig |
+		channels.EachConfig |
+		channels.FlatMapConfig |
+		channels.MapConfig |
+		channels.MergeConfig |
+		channels.ReduceConfig |
+		channels.SelectConfig |
+		channels.TapConfig
+}
+
+// stageOptions registers a stage with the pipeline, and returns the pipeline's default
+// options for the stage followed by the stage's options.  The pipeline's context, panic
+// provider and lifecycle provider can't be overridden, so that a panic or stopping the
+// pipeline stops every stage, and the pipeline knows when every stage has exited.
+func stageOptions[T stageConfiguration](p *Pipeline, defaults []channels.Option[T], opts []channels.Option[T]) []channels.Option[T] {
+	p.addStage()
+
+	result := make([]channels.Option[T], 0, len(defaults)+len(opts)+4)
+	result = append(result, channels.PanicInfoProviderOption[T](p.cfg.panicInfoProvider))
+	result = append(result, defaults...)
+	result = append(result, opts...)
+	return append(result,
+		channels.ContextOption[T](p.ctx),
+		channels.PanicProviderOption[T](p.panics),
+		channels.LifecycleProviderOption[T](p.lifecycle),
+	)
+}
+
+// From creates a source stage reading values from the input channel.  Values are
+// not read from the input channel until the pipeline is run.
+func From[T any](p *Pipeline, inc <-chan T) *Stage[T] {
+	outc := make(chan T, p.cfg.capacity)
+	p.addStage()
+
+	go func() {
+		defer p.stages.Done()
+		defer close(outc)
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.started:
+		}
+
+		for {
+			in, ok := receive(p, inc)
+			if !ok || !send(p, outc, in) {
+				return
+			}
+		}
+	}()
+
+	return &Stage[T]{pipeline: p, outc: outc}
+}
+
+// Map adds a stage applying channels.Map to the output of the `in` stage.
+func Map[TIn any, TOut any](in *Stage[TIn], mapFn func(TIn) (TOut, bool), opts ...channels.Option[channels.MapConfig]) *Stage[TOut] {
+	p := in.pipeline
+	outc := channels.Map(in.outc, mapFn, stageOptions(p, []channels.Option[channels.MapConfig]{
+		channels.ChannelCapacityOption[channels.MapConfig](p.cfg.capacity),
+		channels.StatsProviderOption[channels.MapConfig](p.cfg.statsProvider),
+	}, opts)...)
+
+	return &Stage[TOut]{pipeline: p, outc: outc}
+}
+
+// MapErr adds a stage applying channels.MapErr to the output of the `in` stage.
+func MapErr[TIn any, TOut any](in *Stage[TIn], mapFn func(TIn) (TOut, error), opts ...channels.Option[channels.MapConfig]) *Stage[TOut] {
+	p := in.pipeline
+	outc := channels.MapErr(in.outc, mapFn, stageOptions(p, []channels.Option[channels.MapConfig]{
+		channels.ChannelCapacityOption[channels.MapConfig](p.cfg.capacity),
+		channels.StatsProviderOption[channels.MapConfig](p.cfg.statsProvider),
+		channels.ErrorProviderOption[channels.MapConfig](p.cfg.errorProvider),
+	}, opts)...)
+
+	return &Stage[TOut]{pipeline: p, outc: outc}
+}
+
+// FlatMap adds a stage applying channels.FlatMap to the output of the `in` stage.
+func FlatMap[TIn any, TOut any](in *Stage[TIn], mapFn func(TIn) ([]TOut, bool), opts ...channels.Option[channels.FlatMapConfig]) *Stage[TOut] {
+	p := in.pipeline
+	outc := channels.FlatMap(in.outc, mapFn, stageOptions(p, []channels.Option[channels.FlatMapConfig]{
+		channels.ChannelCapacityOption[channels.FlatMapConfig](p.cfg.capacity),
+		channels.StatsProviderOption[channels.FlatMapConfig](p.cfg.statsProvider),
+	}, opts)...)
+
+	return &Stage[TOut]{pipeline: p, outc: outc}
+}
+
+// Select adds a stage applying channels.Select to the output of the `in` stage.
+func Select[T any](in *Stage[T], selectFn func(T) bool, opts ...channels.Option[channels.SelectConfig]) *Stage[T] {
+	p := in.pipeline
+	outc := channels.Select(in.outc, selectFn, stageOptions(p, []channels.Option[channels.SelectConfig]{
+		channels.ChannelCapacityOption[channels.SelectConfig](p.cfg.capacity),
+		channels.SelectStatsProviderOption(p.cfg.selectStatsProvider),
+	}, opts)...)
+
+	return &Stage[T]{pipeline: p, outc: outc}
+}
+
+// Reject adds a stage applying channels.Reject to the output of the `in` stage.
+func Reject[T any](in *Stage[T], rejectFn func(T) bool, opts ...channels.Option[channels.SelectConfig]) *Stage[T] {
+	p := in.pipeline
+	outc := channels.Reject(in.outc, rejectFn, stageOptions(p, []channels.Option[channels.SelectConfig]{
+		channels.ChannelCapacityOption[channels.SelectConfig](p.cfg.capacity),
+		channels.SelectStatsProviderOption(p.cfg.selectStatsProvider),
+	}, opts)...)
+
+	return &Stage[T]{pipeline: p, outc: outc}
+}
+
+// Tap adds a stage applying channels.Tap to the output of the `in` stage.
+func Tap[T any](in *Stage[T], preFn, postFn func(T), opts ...channels.Option[channels.TapConfig]) *Stage[T] {
+	p := in.pipeline
+	outc := channels.Tap(in.outc, preFn, postFn, stageOptions(p, []channels.Option[channels.TapConfig]{
+		channels.ChannelCapacityOption[channels.TapConfig](p.cfg.capacity),
+		channels.TapStatsProviderOption(p.cfg.tapStatsProvider),
+	}, opts)...)
+
+	return &Stage[T]{pipeline: p, outc: outc}
+}
+
+// Batch adds a stage applying channels.Batch to the output of the `in` stage.
+func Batch[T any](in *Stage[T], batchSize int, maxDelay time.Duration, opts ...channels.Option[channels.BatchConfig]) *Stage[[]T] {
+	p := in.pipeline
+	outc := channels.Batch(in.outc, batchSize, maxDelay, stageOptions(p, []channels.Option[channels.BatchConfig]{
+		channels.ChannelCapacityOption[channels.BatchConfig](p.cfg.capacity),
+		channels.BatchStatsProviderOption(p.cfg.batchStatsProvider),
+	}, opts)...)
+
+	return &Stage[[]T]{pipeline: p, outc: outc}
+}
+
+// Reduce adds a stage applying channels.Reduce to the output of the `in` stage.
+func Reduce[TIn any, TOut any](in *Stage[TIn], reduceFn func(TOut, TIn) (TOut, bool), opts ...channels.Option[channels.ReduceConfig]) *Stage[TOut] {
+	p := in.pipeline
+	outc := channels.Reduce(in.outc, reduceFn, stageOptions(p, []channels.Option[channels.ReduceConfig]{
+		channels.ChannelCapacityOption[channels.ReduceConfig](p.cfg.capacity),
+		channels.StatsProviderOption[channels.ReduceConfig](p.cfg.statsProvider),
+	}, opts)...)
+
+	return &Stage[TOut]{pipeline: p, outc: outc}
+}
+
+// Merge adds a stage applying channels.Merge to the outputs of the `in` stages.
+// Every stage must belong to the pipeline.  Merging no stages adds a stage whose
+// output channel is closed when the pipeline is run.
+func Merge[T any](p *Pipeline, in []*Stage[T], opts ...channels.Option[channels.MergeConfig]) *Stage[T] {
+	if len(in) == 0 {
+		inc := make(chan T)
+		close(inc)
+		return From(p, inc)
+	}
+
+	chans := make([]<-chan T, len(in))
+	for i, stage := range in {
+		if stage.pipeline != p {
+			panic("pipeline: can't merge stages from a different pipeline")
+		}
+		chans[i] = stage.outc
+	}
+
+	outc := channels.Merge(chans, stageOptions(p, []channels.Option[channels.MergeConfig]{
+		channels.ChannelCapacityOption[channels.MergeConfig](p.cfg.capacity),
+	}, opts)...)
+
+	return &Stage[T]{pipeline: p, outc: outc}
+}
+
+// Each adds a final stage applying channels.Each to the output of the `in` stage.
+// The pipeline completes when every Each stage has finished processing values.
+func Each[T any](in *Stage[T], eachFn func(T), opts ...channels.Option[channels.EachConfig]) {
+	p := in.pipeline
+	channels.Each(in.outc, eachFn, stageOptions(p, []channels.Option[channels.EachConfig]{
+		channels.StatsProviderOption[channels.EachConfig](p.cfg.statsProvider),
+	}, opts)...)
+}
+
+// EachErr adds a final stage applying channels.EachErr to the output of the `in` stage.
+// The pipeline completes when every Each stage has finished processing values.
+func EachErr[T any](in *Stage[T], eachFn func(T) error, opts ...channels.Option[channels.EachConfig]) {
+	p := in.pipeline
+	channels.EachErr(in.outc, eachFn, stageOptions(p, []channels.Option[channels.EachConfig]{
+		channels.StatsProviderOption[channels.EachConfig](p.cfg.statsProvider),
+		channels.ErrorProviderOption[channels.EachConfig](p.cfg.errorProvider),
+	}, opts)...)
+}
+
+func receive[T any](p *Pipeline, inc <-chan T) (T, bool) {
+	select {
+	case <-p.ctx.Done():
+		return *new(T), false
+	case in, ok := <-inc:
+		return in, ok
+	}
+}
+
+func send[T any](p *Pipeline, outc chan<- T, val T) bool {
+	select {
+	case <-p.ctx.Done():
+		return false
+	case outc <- val:
+		return true
+	}
+}
@@ -0,0 +1,199 @@
+package pipeline_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/pipeline"
+	"github.com/jonabc/channels/providers"
+)
+
+func TestStages(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	p := pipeline.New()
+
+	source := pipeline.From(p, in)
+	mapped := pipeline.Map(source, func(i int) (string, bool) { return strconv.Itoa(i), true })
+	selected := pipeline.Select(mapped, func(s string) bool { return s != "3" })
+	rejected := pipeline.Reject(selected, func(s string) bool { return s == "4" })
+	flattened := pipeline.FlatMap(rejected, func(s string) ([]string, bool) { return []string{s, s}, true })
+	batched := pipeline.Batch(flattened, 2, time.Minute)
+	reduced := pipeline.Reduce(batched, func(acc string, batch []string) (string, bool) {
+		return acc + batch[0] + batch[1], true
+	})
+
+	var tapped []string
+	tapStage := pipeline.Tap(reduced, func(s string) { tapped = append(tapped, s) }, nil)
+
+	var out []string
+	pipeline.Each(tapStage, func(s string) { out = append(out, s) })
+
+	for i := 1; i <= 5; i++ {
+		in <- i
+	}
+	close(in)
+
+	p.Run()
+	require.NoError(t, p.Wait())
+	require.Equal(t, []string{"11", "1122", "112255"}, out)
+	require.Equal(t, out, tapped)
+}
+
+func TestStagesUseDefaultOptions(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+
+	statsProvider, statsReceiver := providers.NewProvider[channels.Stats](10)
+	defer statsProvider.Close()
+
+	selectStatsProvider, selectStatsReceiver := providers.NewProvider[channels.SelectStats](10)
+	defer selectStatsProvider.Close()
+
+	p := pipeline.New(
+		pipeline.ChannelCapacityOption(5),
+		pipeline.StatsProviderOption(statsProvider),
+		pipeline.SelectStatsProviderOption(selectStatsProvider),
+	)
+
+	source := pipeline.From(p, in)
+	require.Equal(t, 5, cap(source.Channel()))
+
+	mapped := pipeline.Map(source, func(i int) (int, bool) { return i, true })
+	require.Equal(t, 5, cap(mapped.Channel()))
+
+	// stage options override the pipeline's defaults
+	selected := pipeline.Select(mapped, func(i int) bool { return true },
+		channels.ChannelCapacityOption[channels.SelectConfig](1),
+		channels.NameOption[channels.SelectConfig]("all"),
+	)
+	require.Equal(t, 1, cap(selected.Channel()))
+
+	in <- 1
+	close(in)
+
+	p.Run()
+	require.Equal(t, 1, <-selected.Channel())
+
+	stats := <-statsReceiver.Channel()
+	require.Equal(t, channels.MapOperator, stats.Operator)
+
+	selectStats := <-selectStatsReceiver.Channel()
+	require.Equal(t, "all", selectStats.Name)
+	require.True(t, selectStats.Selected)
+}
+
+func TestMapErrAndEachErrStages(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+
+	provider, receiver := providers.NewProvider[channels.Failure[any]](10)
+	defer provider.Close()
+
+	p := pipeline.New(pipeline.ErrorProviderOption(provider))
+
+	failed := errors.New("failed")
+	mapped := pipeline.MapErr(pipeline.From(p, in), func(i int) (string, error) {
+		if i == 1 {
+			return "", failed
+		}
+		return strconv.Itoa(i), nil
+	})
+	pipeline.EachErr(mapped, func(s string) error { return failed })
+
+	in <- 1
+	in <- 2
+	close(in)
+
+	p.Run()
+	require.NoError(t, p.Wait())
+
+	// failures from stages with different input types are sent to the same provider
+	failures := map[channels.OperatorKind]any{}
+	for i := 0; i < 2; i++ {
+		failure := <-receiver.Channel()
+		failures[failure.Operator] = failure.Input
+	}
+	require.Equal(t, map[channels.OperatorKind]any{
+		channels.MapErrOperator:  1,
+		channels.EachErrOperator: "2",
+	}, failures)
+}
+
+func TestMergeStages(t *testing.T) {
+	t.Parallel()
+
+	in1 := make(chan int, 10)
+	in2 := make(chan int, 10)
+	p := pipeline.New()
+
+	merged := pipeline.Merge(p, []*pipeline.Stage[int]{pipeline.From(p, in1), pipeline.From(p, in2)})
+
+	var mu sync.Mutex
+	var out []int
+	pipeline.Each(merged, func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		out = append(out, i)
+	}, channels.ConcurrencyOption[channels.EachConfig](2, channels.UnorderedOutput))
+
+	in1 <- 1
+	in2 <- 2
+	close(in1)
+	close(in2)
+
+	p.Run()
+	require.NoError(t, p.Wait())
+	require.ElementsMatch(t, []int{1, 2}, out)
+}
+
+func TestMergeStagesFromDifferentPipelines(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	p := pipeline.New()
+	require.Panics(t, func() {
+		pipeline.Merge(p, []*pipeline.Stage[int]{
+			pipeline.From(p, in),
+			pipeline.From(pipeline.New(), in),
+		})
+	})
+}
+
+func TestMergeNoStages(t *testing.T) {
+	t.Parallel()
+
+	p := pipeline.New()
+	merged := pipeline.Merge[int](p, nil)
+
+	var out []int
+	pipeline.Each(merged, func(i int) { out = append(out, i) })
+
+	p.Run()
+	require.NoError(t, p.Wait())
+	require.Empty(t, out)
+}
+
+func TestEachAfterRun(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	p := pipeline.New()
+	source := pipeline.From(p, in)
+	p.Run()
+
+	require.Panics(t, func() { pipeline.Each(source, func(i int) {}) })
+}
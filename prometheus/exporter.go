@@ -0,0 +1,288 @@
+// Package prometheus serves stats reported by channels functions in the Prometheus
+// text exposition format, without depending on a Prometheus client library.
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+)
+
+// DefaultBuckets are the default histogram bucket upper bounds, in seconds.
+var DefaultBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
+// Config contains user configurable options for an Exporter.
+type Config struct {
+	namespace string
+	buckets   []float64
+}
+
+type Option func(*Config)
+
+// Specify the prefix of exported metric names.  The default namespace is "channels".
+func NamespaceOption(namespace string) Option {
+	return func(cfg *Config) {
+		cfg.namespace = namespace
+	}
+}
+
+// Specify the upper bounds of histogram buckets, in seconds.  The default buckets
+// are DefaultBuckets.
+func BucketsOption(buckets []float64) Option {
+	return func(cfg *Config) {
+		cfg.buckets = buckets
+	}
+}
+
+const (
+	counterKind   = "counter"
+	gaugeKind     = "gauge"
+	histogramKind = "histogram"
+)
+
+// Exporter aggregates stats reported by channels functions, and serves them as
+// metrics in the Prometheus text exposition format.  Pass a provider returned from
+// the exporter, e.g. StatsProvider or BatchStatsProvider, to the stats provider
+// option of a channels function.  Metrics are labeled with the operator and name
+// of the function that reported them, see channels.NameOption.
+type Exporter struct {
+	mu        sync.Mutex
+	namespace string
+	buckets   []float64
+	families  map[string]*family
+}
+
+type family struct {
+	name   string
+	help   string
+	kind   string
+	series map[string]*series
+}
+
+type series struct {
+	labels string
+	// the value of counters and gauges
+	value float64
+	// the non-cumulative count of observations in each bucket of histograms,
+	// with a final bucket for observations larger than every upper bound
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewExporter returns an Exporter with no recorded metrics.
+func NewExporter(opts ...Option) *Exporter {
+	cfg := &Config{namespace: "channels", buckets: DefaultBuckets}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	buckets := append([]float64(nil), cfg.buckets...)
+	sort.Float64s(buckets)
+
+	return &Exporter{
+		namespace: cfg.namespace,
+		buckets:   buckets,
+		families:  make(map[string]*family),
+	}
+}
+
+// Returns a provider that records Stats.  Closing the returned provider has no effect.
+func (e *Exporter) StatsProvider() providers.Provider[channels.Stats] {
+	return &exporterProvider[channels.Stats]{exporter: e, record: func(s channels.Stats) {
+		labels := renderLabels(s.Operator, s.Name)
+		e.add("values_total", "Number of values processed.", labels, 1)
+		e.observe("duration_seconds", "Duration of operations.", labels, s.Duration)
+		e.set("queue_length", "Length of the input channel when an operation completed.", labels, float64(s.QueueLength))
+	}}
+}
+
+// Returns a provider that records BatchStats.  Closing the returned provider has no effect.
+func (e *Exporter) BatchStatsProvider() providers.Provider[channels.BatchStats] {
+	return &exporterProvider[channels.BatchStats]{exporter: e, record: func(s channels.BatchStats) {
+		labels := renderLabels(s.Operator, s.Name)
+		e.add("batches_total", "Number of batches written.", labels, 1)
+		e.add("batch_values_total", "Number of values written in batches.", labels, float64(s.BatchSize))
+		e.observe("duration_seconds", "Duration of operations.", labels, s.Duration)
+		e.set("queue_length", "Length of the input channel when an operation completed.", labels, float64(s.QueueLength))
+	}}
+}
+
+// Returns a provider that records DebounceStats.  Closing the returned provider has no effect.
+func (e *Exporter) DebounceStatsProvider() providers.Provider[channels.DebounceStats] {
+	return &exporterProvider[channels.DebounceStats]{exporter: e, record: func(s channels.DebounceStats) {
+		labels := renderLabels(s.Operator, s.Name)
+		e.add("values_total", "Number of values processed.", labels, 1)
+		e.add("debounced_values_total", "Number of values debounced into written values.", labels, float64(s.Count))
+		e.observe("debounce_delay_seconds", "Delay of debounced values.", labels, s.Delay)
+		e.set("queue_length", "Length of the input channel when an operation completed.", labels, float64(s.QueueLength))
+	}}
+}
+
+// Returns a provider that records SelectStats.  Closing the returned provider has no effect.
+func (e *Exporter) SelectStatsProvider() providers.Provider[channels.SelectStats] {
+	return &exporterProvider[channels.SelectStats]{exporter: e, record: func(s channels.SelectStats) {
+		labels := renderLabels(s.Operator, s.Name)
+		e.add("values_total", "Number of values processed.", labels, 1)
+		if s.Selected {
+			e.add("selected_total", "Number of values selected.", labels, 1)
+		}
+		e.observe("duration_seconds", "Duration of operations.", labels, s.Duration)
+		e.set("queue_length", "Length of the input channel when an operation completed.", labels, float64(s.QueueLength))
+	}}
+}
+
+// Returns a provider that records TapStats.  Closing the returned provider has no effect.
+func (e *Exporter) TapStatsProvider() providers.Provider[channels.TapStats] {
+	return &exporterProvider[channels.TapStats]{exporter: e, record: func(s channels.TapStats) {
+		labels := renderLabels(s.Operator, s.Name)
+		e.add("values_total", "Number of values processed.", labels, 1)
+		e.observe("tap_pre_duration_seconds", "Duration of tap functions called before values are written.", labels, s.PreDuration)
+		e.observe("tap_post_duration_seconds", "Duration of tap functions called after values are written.", labels, s.PostDuration)
+		e.set("queue_length", "Length of the input channel when an operation completed.", labels, float64(s.QueueLength))
+	}}
+}
+
+// ServeHTTP writes every recorded metric in the Prometheus text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.WriteTo(w)
+}
+
+// WriteTo writes every recorded metric in the Prometheus text exposition format to `w`.
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	e.mu.Lock()
+	names := make([]string, 0, len(e.families))
+	for name := range e.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		e.families[name].write(&b, e.buckets)
+	}
+	e.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (e *Exporter) getFamily(name string, help string, kind string) *family {
+	name = e.namespace + "_" + name
+	if e.namespace == "" {
+		name = strings.TrimPrefix(name, "_")
+	}
+
+	f, ok := e.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind, series: make(map[string]*series)}
+		e.families[name] = f
+	}
+
+	return f
+}
+
+func (f *family) get(labels string) *series {
+	s, ok := f.series[labels]
+	if !ok {
+		s = &series{labels: labels}
+		f.series[labels] = s
+	}
+
+	return s
+}
+
+// add increments a counter.  The exporter's lock must be held.
+func (e *Exporter) add(name string, help string, labels string, delta float64) {
+	e.getFamily(name, help, counterKind).get(labels).value += delta
+}
+
+// set sets a gauge.  The exporter's lock must be held.
+func (e *Exporter) set(name string, help string, labels string, value float64) {
+	e.getFamily(name, help, gaugeKind).get(labels).value = value
+}
+
+// observe records a duration in a histogram.  The exporter's lock must be held.
+func (e *Exporter) observe(name string, help string, labels string, d time.Duration) {
+	s := e.getFamily(name, help, histogramKind).get(labels)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(e.buckets)+1)
+	}
+
+	seconds := d.Seconds()
+	s.buckets[sort.SearchFloat64s(e.buckets, seconds)]++
+	s.sum += seconds
+	s.count++
+}
+
+func (f *family) write(b *strings.Builder, buckets []float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", f.name, f.kind)
+
+	labels := make([]string, 0, len(f.series))
+	for l := range f.series {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	for _, l := range labels {
+		s := f.series[l]
+		if f.kind != histogramKind {
+			fmt.Fprintf(b, "%s{%s} %s\n", f.name, s.labels, formatFloat(s.value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, upper := range buckets {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", f.name, s.labels, formatFloat(upper), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", f.name, s.labels, s.count)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", f.name, s.labels, formatFloat(s.sum))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", f.name, s.labels, s.count)
+	}
+}
+
+func renderLabels(operator channels.OperatorKind, name string) string {
+	return fmt.Sprintf("operator=\"%s\",name=\"%s\"", escapeLabel(string(operator)), escapeLabel(name))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// exporterProvider adapts an Exporter to a provider for a single stats type.
+type exporterProvider[T any] struct {
+	exporter *Exporter
+	record   func(T)
+}
+
+func (p *exporterProvider[T]) IsClosed() bool {
+	return false
+}
+
+func (p *exporterProvider[T]) Close() {}
+
+func (p *exporterProvider[T]) Provide(stats T) bool {
+	p.exporter.mu.Lock()
+	defer p.exporter.mu.Unlock()
+
+	p.record(stats)
+	return true
+}
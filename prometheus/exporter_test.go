@@ -0,0 +1,113 @@
+package prometheus_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, exporter *prometheus.Exporter) string {
+	t.Helper()
+
+	server := httptest.NewServer(exporter)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	exporter := prometheus.NewExporter(prometheus.BucketsOption([]float64{0.01, 0.1}))
+
+	stats := exporter.StatsProvider()
+	require.True(t, stats.Provide(channels.Stats{Operator: channels.MapOperator, Name: "parse", Duration: 5 * time.Millisecond, QueueLength: 3}))
+	require.True(t, stats.Provide(channels.Stats{Operator: channels.MapOperator, Name: "parse", Duration: 50 * time.Millisecond, QueueLength: 1}))
+
+	selectStats := exporter.SelectStatsProvider()
+	require.True(t, selectStats.Provide(channels.SelectStats{Operator: channels.SelectOperator, Duration: time.Second, Selected: true}))
+	require.True(t, selectStats.Provide(channels.SelectStats{Operator: channels.SelectOperator, Duration: time.Second}))
+
+	expected := `# HELP channels_duration_seconds Duration of operations.
+# TYPE channels_duration_seconds histogram
+channels_duration_seconds_bucket{operator="Map",name="parse",le="0.01"} 1
+channels_duration_seconds_bucket{operator="Map",name="parse",le="0.1"} 2
+channels_duration_seconds_bucket{operator="Map",name="parse",le="+Inf"} 2
+channels_duration_seconds_sum{operator="Map",name="parse"} 0.055
+channels_duration_seconds_count{operator="Map",name="parse"} 2
+channels_duration_seconds_bucket{operator="Select",name="",le="0.01"} 0
+channels_duration_seconds_bucket{operator="Select",name="",le="0.1"} 0
+channels_duration_seconds_bucket{operator="Select",name="",le="+Inf"} 2
+channels_duration_seconds_sum{operator="Select",name=""} 2
+channels_duration_seconds_count{operator="Select",name=""} 2
+# HELP channels_queue_length Length of the input channel when an operation completed.
+# TYPE channels_queue_length gauge
+channels_queue_length{operator="Map",name="parse"} 1
+channels_queue_length{operator="Select",name=""} 0
+# HELP channels_selected_total Number of values selected.
+# TYPE channels_selected_total counter
+channels_selected_total{operator="Select",name=""} 1
+# HELP channels_values_total Number of values processed.
+# TYPE channels_values_total counter
+channels_values_total{operator="Map",name="parse"} 2
+channels_values_total{operator="Select",name=""} 2
+`
+	require.Equal(t, expected, scrape(t, exporter))
+}
+
+func TestExporterBatchDebounceAndTapStats(t *testing.T) {
+	t.Parallel()
+
+	exporter := prometheus.NewExporter(
+		prometheus.NamespaceOption("app"),
+		prometheus.BucketsOption([]float64{1}),
+	)
+
+	require.True(t, exporter.BatchStatsProvider().Provide(channels.BatchStats{Operator: channels.BatchOperator, BatchSize: 5}))
+	require.True(t, exporter.DebounceStatsProvider().Provide(channels.DebounceStats{Operator: channels.DebounceOperator, Count: 3}))
+	require.True(t, exporter.TapStatsProvider().Provide(channels.TapStats{Operator: channels.TapOperator, Name: `"quoted"`}))
+
+	body := scrape(t, exporter)
+	require.Contains(t, body, `app_batches_total{operator="Batch",name=""} 1`)
+	require.Contains(t, body, `app_batch_values_total{operator="Batch",name=""} 5`)
+	require.Contains(t, body, `app_debounced_values_total{operator="Debounce",name=""} 3`)
+	require.Contains(t, body, `app_debounce_delay_seconds_count{operator="Debounce",name=""} 1`)
+	require.Contains(t, body, `app_tap_pre_duration_seconds_count{operator="Tap",name="\"quoted\""} 1`)
+	require.Contains(t, body, `app_tap_post_duration_seconds_bucket{operator="Tap",name="\"quoted\"",le="1"} 1`)
+}
+
+func TestExporterWithChannelsFunction(t *testing.T) {
+	t.Parallel()
+
+	exporter := prometheus.NewExporter()
+
+	inc := make(chan int)
+	out := channels.Map(inc, func(i int) (int, bool) { return i * 2, true },
+		channels.StatsProviderOption[channels.MapConfig](exporter.StatsProvider()),
+		channels.NameOption[channels.MapConfig]("double"),
+	)
+
+	go func() {
+		defer close(inc)
+		for i := 0; i < 3; i++ {
+			inc <- i
+		}
+	}()
+	for range out {
+	}
+
+	require.Contains(t, scrape(t, exporter), `channels_values_total{operator="Map",name="double"} 3`)
+}
@@ -0,0 +1,226 @@
+package providers
+
+import (
+	"sync"
+)
+
+type OverflowPolicy byte
+
+const (
+	// Provide blocks until the subscriber's channel can be written to.
+	BlockOnOverflow OverflowPolicy = iota
+	// New values are dropped when the subscriber's channel is full.
+	DropNewestOnOverflow
+	// The oldest unread value is dropped to make room for a new value when the
+	// subscriber's channel is full.
+	DropOldestOnOverflow
+)
+
+// PubSubProvider is a provider that writes each provided value to every subscribed
+// receiver.  Receivers can subscribe and unsubscribe at any time, and each receiver
+// has its own channel size and overflow policy.
+type PubSubProvider[T any] struct {
+	// mu is held for reading while the subscribers are copied to provide a value,
+	// and for writing while subscribers are added or removed
+	mu          sync.RWMutex
+	subscribers map[*subscription[T]]struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+type subscription[T any] struct {
+	provider *PubSubProvider[T]
+	outc     chan T
+	done     chan struct{}
+	policy   OverflowPolicy
+	mu       sync.Mutex
+	// sending is held for reading while a value is written to outc, and for
+	// writing while outc is closed
+	sending   sync.RWMutex
+	closeOnce sync.Once
+}
+
+// The pub/sub provider writes each provided value to every receiver subscribed with
+// Subscribe.  Values provided without any subscribers are dropped.
+func NewPubSubProvider[T any]() *PubSubProvider[T] {
+	return &PubSubProvider[T]{
+		subscribers: make(map[*subscription[T]]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Returns true if the provider has been closed, false otherwise
+func (p *PubSubProvider[T]) IsClosed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close the provider, closing every subscribed receiver
+func (p *PubSubProvider[T]) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		for sub := range p.subscribers {
+			sub.closeLocked()
+		}
+	})
+}
+
+// Provide a value to every subscribed receiver
+func (p *PubSubProvider[T]) Provide(val T) bool {
+	// Give preference to the done channel to signal the provider is closed
+	select {
+	case <-p.done:
+		return false
+	default:
+	}
+
+	// write to the subscribers without holding the lock, so that a subscriber
+	// blocking Provide doesn't block subscribing and unsubscribing
+	p.mu.RLock()
+	subscribers := make([]*subscription[T], 0, len(p.subscribers))
+	for sub := range p.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	p.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		sub.provide(val)
+	}
+
+	return true
+}
+
+// Subscribe a new receiver with a channel of `size` capacity, which handles a full
+// channel according to `policy`.  Subscribing after the provider is closed returns
+// a closed receiver.
+func (p *PubSubProvider[T]) Subscribe(size int, policy OverflowPolicy) Receiver[T] {
+	if policy == DropOldestOnOverflow {
+		size = max(size, 1)
+	}
+
+	sub := &subscription[T]{
+		provider: p,
+		outc:     make(chan T, size),
+		done:     make(chan struct{}),
+		policy:   policy,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.IsClosed() {
+		sub.closeLocked()
+		return sub
+	}
+
+	p.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe a receiver returned from Subscribe, closing the receiver.  This is
+// equivalent to calling the receiver's Close function.
+func (p *PubSubProvider[T]) Unsubscribe(receiver Receiver[T]) {
+	if sub, ok := receiver.(*subscription[T]); ok && sub.provider == p {
+		sub.Close()
+	}
+}
+
+// Returns true if the receiver has been closed, false otherwise
+func (s *subscription[T]) IsClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close the receiver, unsubscribing it from the provider
+func (s *subscription[T]) Close() {
+	// close the done channel before closing the channel, to unblock a call to
+	// Provide blocked writing to the channel
+	s.signalDone()
+
+	s.provider.mu.Lock()
+	defer s.provider.mu.Unlock()
+
+	s.closeLocked()
+}
+
+// Returns a read-only channel receiving values from the provider
+func (s *subscription[T]) Channel() <-chan T {
+	return s.outc
+}
+
+func (s *subscription[T]) signalDone() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+}
+
+// closeLocked removes the subscription from the provider and closes its channel.
+// The provider's lock must be held for writing.
+func (s *subscription[T]) closeLocked() {
+	s.closeOnce.Do(func() {
+		s.signalDone()
+		delete(s.provider.subscribers, s)
+
+		s.sending.Lock()
+		defer s.sending.Unlock()
+		close(s.outc)
+	})
+}
+
+func (s *subscription[T]) provide(val T) {
+	s.sending.RLock()
+	defer s.sending.RUnlock()
+
+	// the subscription may have been closed after it was copied by Provide
+	if s.IsClosed() {
+		return
+	}
+
+	switch s.policy {
+	case DropNewestOnOverflow:
+		select {
+		case s.outc <- val:
+		default:
+		}
+	case DropOldestOnOverflow:
+		// serialize writers so that a value dropped to make room isn't taken by another writer
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for {
+			select {
+			case s.outc <- val:
+				return
+			default:
+			}
+
+			select {
+			case <-s.outc:
+			default:
+			}
+		}
+	default:
+		select {
+		case <-s.done:
+		case <-s.provider.done:
+		case s.outc <- val:
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package providers_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels/providers"
+)
+
+func TestPubSubProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := providers.NewPubSubProvider[int]()
+	defer provider.Close()
+
+	// values provided without subscribers are dropped
+	require.True(t, provider.Provide(10))
+
+	blocking := provider.Subscribe(0, providers.BlockOnOverflow)
+	newest := provider.Subscribe(1, providers.DropNewestOnOverflow)
+	oldest := provider.Subscribe(1, providers.DropOldestOnOverflow)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.Equal(t, 20, <-blocking.Channel())
+		require.Equal(t, 30, <-blocking.Channel())
+	}()
+
+	require.True(t, provider.Provide(20))
+	require.True(t, provider.Provide(30))
+	wg.Wait()
+
+	require.Equal(t, 20, <-newest.Channel())
+	require.Equal(t, 30, <-oldest.Channel())
+
+	provider.Unsubscribe(blocking)
+	require.True(t, blocking.IsClosed())
+	_, ok := <-blocking.Channel()
+	require.False(t, ok)
+
+	// unsubscribed receivers don't block other receivers
+	require.True(t, provider.Provide(40))
+	require.Equal(t, 40, <-newest.Channel())
+	require.Equal(t, 40, <-oldest.Channel())
+
+	provider.Close()
+	require.True(t, provider.IsClosed())
+	require.False(t, provider.Provide(50))
+
+	_, ok = <-newest.Channel()
+	require.False(t, ok)
+
+	closed := provider.Subscribe(1, providers.BlockOnOverflow)
+	require.True(t, closed.IsClosed())
+}
+
+func TestPubSubProviderCloseReceiverUnblocksProvide(t *testing.T) {
+	t.Parallel()
+
+	provider := providers.NewPubSubProvider[int]()
+	defer provider.Close()
+
+	receiver := provider.Subscribe(0, providers.BlockOnOverflow)
+
+	provided := make(chan bool)
+	go func() {
+		provided <- provider.Provide(10)
+	}()
+
+	receiver.Close()
+	require.True(t, <-provided)
+}
+
+func TestPubSubProviderBlockedProvideDoesNotBlockSubscribers(t *testing.T) {
+	t.Parallel()
+
+	provider := providers.NewPubSubProvider[int]()
+	defer provider.Close()
+
+	slow := provider.Subscribe(0, providers.BlockOnOverflow)
+
+	provided := make(chan bool)
+	go func() {
+		provided <- provider.Provide(10)
+	}()
+
+	// subscribing and unsubscribing don't wait for the slow receiver
+	other := provider.Subscribe(1, providers.DropNewestOnOverflow)
+	provider.Unsubscribe(other)
+	require.True(t, other.IsClosed())
+
+	select {
+	case <-provided:
+		require.Fail(t, "provide didn't block on the slow receiver")
+	default:
+	}
+
+	provider.Unsubscribe(slow)
+	require.True(t, <-provided)
+}
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RingProvider is a provider that overwrites the oldest unread values when the
+// receiving channel is full.
+type RingProvider[T any] struct {
+	*providerReceiver[T, T]
+	mu          sync.Mutex
+	overwritten atomic.Uint64
+}
+
+// The ring provider buffers up to `size` unread values, and drops the oldest
+// unread value to make room for a new value when the buffer is full.  A size
+// less than 1 is treated as 1.
+func NewRingProvider[T any](size int) (*RingProvider[T], Receiver[T]) {
+	ring := &RingProvider[T]{}
+	ring.providerReceiver = newProviderReceiver(max(size, 1), func(val T, provider *providerReceiver[T, T]) bool {
+		// serialize writers so that a value dropped to make room isn't taken by another writer
+		ring.mu.Lock()
+		defer ring.mu.Unlock()
+
+		for {
+			select {
+			case provider.outc <- val:
+				return true
+			default:
+			}
+
+			select {
+			case <-provider.outc:
+				ring.overwritten.Add(1)
+			default:
+				// the receiver read a value, making room for the new value
+			}
+		}
+	})
+
+	return ring, ring
+}
+
+// Returns the number of unread values that have been overwritten
+func (r *RingProvider[T]) Overwritten() uint64 {
+	return r.overwritten.Load()
+}
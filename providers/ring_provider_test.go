@@ -0,0 +1,79 @@
+package providers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels/providers"
+)
+
+func TestRingProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewRingProvider[int](2)
+	defer provider.Close()
+
+	require.True(t, provider.Provide(10))
+	require.True(t, provider.Provide(20))
+	require.True(t, provider.Provide(30))
+	require.True(t, provider.Provide(40))
+
+	require.Equal(t, uint64(2), provider.Overwritten())
+	require.Equal(t, 30, <-receiver.Channel())
+	require.Equal(t, 40, <-receiver.Channel())
+}
+
+func TestRingProviderOverwritesOldestValues(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewRingProvider[int](3)
+	defer provider.Close()
+
+	for i := 1; i <= 7; i++ {
+		require.True(t, provider.Provide(i))
+	}
+	require.Equal(t, uint64(4), provider.Overwritten())
+
+	// reading a value makes room, so the next value doesn't overwrite
+	require.Equal(t, 5, <-receiver.Channel())
+	require.True(t, provider.Provide(8))
+	require.Equal(t, uint64(4), provider.Overwritten())
+
+	require.True(t, provider.Provide(9))
+	require.Equal(t, uint64(5), provider.Overwritten())
+
+	require.Equal(t, 7, <-receiver.Channel())
+	require.Equal(t, 8, <-receiver.Channel())
+	require.Equal(t, 9, <-receiver.Channel())
+}
+
+func TestRingProviderSizeLessThanOne(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewRingProvider[int](0)
+	defer provider.Close()
+
+	require.True(t, provider.Provide(10))
+	require.True(t, provider.Provide(20))
+
+	require.Equal(t, uint64(1), provider.Overwritten())
+	require.Equal(t, 20, <-receiver.Channel())
+}
+
+func TestRingProviderProvideAfterClose(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewRingProvider[int](1)
+	require.True(t, provider.Provide(10))
+
+	provider.Close()
+	require.True(t, provider.IsClosed())
+	require.False(t, provider.Provide(20))
+	require.Equal(t, uint64(0), provider.Overwritten())
+
+	// values provided before closing can still be read
+	require.Equal(t, 10, <-receiver.Channel())
+	_, ok := <-receiver.Channel()
+	require.False(t, ok)
+}
@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// TimeoutProvider is a provider that blocks for up to a timeout when the
+// receiving channel blocks, and drops the value when the timeout elapses.
+type TimeoutProvider[T any] struct {
+	*providerReceiver[T, T]
+	timeouts atomic.Uint64
+}
+
+// The timeout provider blocks calls to Provide for up to `timeout` duration
+// when the underlying channel blocks.  Provide returns false if the value is
+// dropped because the timeout elapsed.
+func NewTimeoutProvider[T any](size int, timeout time.Duration) (*TimeoutProvider[T], Receiver[T]) {
+	provider := &TimeoutProvider[T]{}
+	provider.providerReceiver = newProviderReceiver(size, func(val T, receiver *providerReceiver[T, T]) bool {
+		select {
+		case receiver.outc <- val:
+			return true
+		default:
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		return provider.wait(val, timer.C, nil)
+	})
+
+	return provider, provider
+}
+
+// Provide a value to receivers, blocking until the value is written, the provider
+// is closed or `ctx` is done.  The provider's timeout doesn't apply to ProvideContext.
+// Returns false if the value isn't written.
+func (p *TimeoutProvider[T]) ProvideContext(ctx context.Context, val T) bool {
+	// Give preference to the done channel to signal the provider is closed
+	select {
+	case <-p.done:
+		return false
+	default:
+		return p.wait(val, nil, ctx.Done())
+	}
+}
+
+// Returns the number of values dropped because the timeout elapsed, or because
+// the context passed to ProvideContext was done
+func (p *TimeoutProvider[T]) Timeouts() uint64 {
+	return p.timeouts.Load()
+}
+
+func (p *TimeoutProvider[T]) wait(val T, timeout <-chan time.Time, ctxDone <-chan struct{}) bool {
+	select {
+	case <-p.done:
+		return false
+	case p.outc <- val:
+		return true
+	case <-timeout:
+	case <-ctxDone:
+	}
+
+	p.timeouts.Add(1)
+	return false
+}
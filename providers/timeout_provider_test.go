@@ -0,0 +1,43 @@
+package providers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels/providers"
+)
+
+func TestTimeoutProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewTimeoutProvider[int](1, 10*time.Millisecond)
+	defer provider.Close()
+
+	require.True(t, provider.Provide(10))
+	require.False(t, provider.Provide(20))
+	require.Equal(t, uint64(1), provider.Timeouts())
+
+	require.Equal(t, 10, <-receiver.Channel())
+	require.True(t, provider.Provide(30))
+	require.Equal(t, 30, <-receiver.Channel())
+}
+
+func TestTimeoutProviderProvideContext(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewTimeoutProvider[int](0, time.Hour)
+	defer provider.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.False(t, provider.ProvideContext(ctx, 10))
+	require.Equal(t, uint64(1), provider.Timeouts())
+
+	go func() {
+		require.True(t, provider.ProvideContext(context.Background(), 20))
+	}()
+	require.Equal(t, 20, <-receiver.Channel())
+}
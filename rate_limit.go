@@ -0,0 +1,173 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+// ErrRateLimited is the error reported in a Failure for values dropped by RateLimit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+type RateLimitMode byte
+
+const (
+	// Values wait for the rate limit before they are written to the output channel,
+	// applying backpressure to the input channel.
+	BlockOnRateLimit RateLimitMode = iota
+	// Values that exceed the rate limit are dropped, and sent to the provider
+	// configured with ErrorProviderOption.
+	DropOnRateLimit
+)
+
+type RateLimitConfig struct {
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[RateLimitStats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	clock                clock.Clock
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	mode                 RateLimitMode
+}
+
+// RateLimit reads values from the input channel and writes them to the output channel
+// at a rate of at most `count` values per `interval` duration, using a token bucket.
+// Up to `burst` values can be written without waiting after a period without values.
+// A `count` or `burst` less than 1 is treated as 1.
+// By default values wait for the rate limit, see RateLimitModeOption to drop values
+// that exceed the rate limit instead.  The output channel is unbuffered by default,
+// and will be closed when the input channel is closed and drained.
+func RateLimit[T any](inc <-chan T, count int, interval time.Duration, burst int, opts ...Option[RateLimitConfig]) <-chan T {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan T, cfg.capacity)
+	operator := cfg.operator.or(RateLimitOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	errorProvider := failureProvider[T](cfg.errorProvider)
+	mode := cfg.mode
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	bucket := newTokenBucket(count, interval, burst, clk.Now())
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok {
+				return
+			}
+
+			start := clk.Now()
+			wait := bucket.take(start)
+			if wait > 0 && mode == DropOnRateLimit {
+				tryProvideFailure(Failure[T]{Operator: operator, Name: name, Input: in, Err: ErrRateLimited, Time: start}, errorProvider)
+				tryProvideStats(RateLimitStats{Operator: operator, Name: name, Dropped: true, QueueLength: len(inc)}, statsProvider)
+				continue
+			}
+
+			if wait > 0 {
+				if !sleep(ctx, clk, wait) {
+					return
+				}
+
+				// the wait is measured before writing the value, so that it doesn't
+				// include time spent blocked on the output channel
+				now := clk.Now()
+				bucket.consume(now)
+				wait = now.Sub(start)
+			}
+
+			if !send(ctx, outc, in) {
+				return
+			}
+
+			tryProvideStats(RateLimitStats{Operator: operator, Name: name, Wait: wait, QueueLength: len(inc)}, statsProvider)
+		}
+	}()
+
+	return outc
+}
+
+// tokenBucket holds up to `burst` tokens, refilled at `count` tokens per `interval`.
+type tokenBucket struct {
+	tokens   float64
+	burst    float64
+	perToken time.Duration
+	last     time.Time
+}
+
+func newTokenBucket(count int, interval time.Duration, burst int, now time.Time) *tokenBucket {
+	if count < 1 {
+		count = 1
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		tokens:   float64(burst),
+		burst:    float64(burst),
+		perToken: interval / time.Duration(count),
+		last:     now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.perToken <= 0 {
+		b.tokens = b.burst
+	} else if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+float64(elapsed)/float64(b.perToken))
+	}
+	b.last = now
+}
+
+// take removes a token from the bucket, returning zero if a token was available
+// or the duration to wait until a token is available.
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) * float64(b.perToken))
+}
+
+// consume removes a token from the bucket after waiting for the duration returned by take.
+func (b *tokenBucket) consume(now time.Time) {
+	b.refill(now)
+	b.tokens = max(0, b.tokens-1)
+}
+
+// sleep waits for `d` duration, returning false if the context is done first.
+func sleep(ctx context.Context, clk clock.Clock, d time.Duration) bool {
+	timer := clk.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
@@ -0,0 +1,187 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	provider, receiver := providers.NewProvider[channels.RateLimitStats](10)
+	defer provider.Close()
+
+	out := channels.RateLimit(in, 10, time.Second, 2,
+		channels.ClockOption[channels.RateLimitConfig](clk),
+		channels.RateLimitStatsProviderOption(provider),
+	)
+
+	// the burst is written without waiting
+	in <- 1
+	in <- 2
+	in <- 3
+	require.Equal(t, 1, <-out)
+	require.Equal(t, 2, <-out)
+	require.Equal(t, time.Duration(0), (<-receiver.Channel()).Wait)
+	require.Equal(t, time.Duration(0), (<-receiver.Channel()).Wait)
+
+	// the next value waits for a token
+	clk.BlockUntil(1)
+	clk.Advance(99 * time.Millisecond)
+	require.Len(t, out, 0)
+
+	clk.Advance(time.Millisecond)
+	require.Equal(t, 3, <-out)
+
+	stats := <-receiver.Channel()
+	require.Equal(t, channels.RateLimitOperator, stats.Operator)
+	require.Equal(t, 100*time.Millisecond, stats.Wait)
+	require.False(t, stats.Dropped)
+}
+
+func TestRateLimitTreatsCountAndBurstLessThanOneAsOne(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	out := channels.RateLimit(in, 0, time.Second, 0,
+		channels.ClockOption[channels.RateLimitConfig](clk),
+	)
+
+	// a burst of one value is written without waiting
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+
+	// the next value waits for a token at one value per interval
+	clk.BlockUntil(1)
+	clk.Advance(999 * time.Millisecond)
+	require.Len(t, out, 0)
+
+	clk.Advance(time.Millisecond)
+	require.Equal(t, 2, <-out)
+}
+
+func TestRateLimitStatsExcludeOutputBackpressure(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	provider, receiver := providers.NewProvider[channels.RateLimitStats](10)
+	defer provider.Close()
+
+	out := channels.RateLimit(in, 10, time.Second, 1,
+		channels.ClockOption[channels.RateLimitConfig](clk),
+		channels.RateLimitStatsProviderOption(provider),
+	)
+
+	// 1 doesn't wait for a token, but the consumer is slow to read it
+	in <- 1
+	require.Eventually(t, func() bool { return len(in) == 0 }, time.Second, time.Millisecond)
+	clk.Advance(time.Second)
+	require.Equal(t, 1, <-out)
+	require.Equal(t, time.Duration(0), (<-receiver.Channel()).Wait)
+}
+
+func TestRateLimitRefillsBurst(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	out := channels.RateLimit(in, 10, time.Second, 2,
+		channels.ClockOption[channels.RateLimitConfig](clk),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, []int{1, 2}, []int{<-out, <-out})
+
+	clk.Advance(time.Second)
+	in <- 3
+	in <- 4
+	require.Equal(t, []int{3, 4}, []int{<-out, <-out})
+	require.Equal(t, 0, clk.Waiters())
+}
+
+func TestRateLimitModeOptionWithDropOnRateLimit(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	clk := clock.NewManual(time.Now())
+	errorProvider, errorReceiver := providers.NewProvider[channels.Failure[int]](10)
+	defer errorProvider.Close()
+
+	statsProvider, statsReceiver := providers.NewProvider[channels.RateLimitStats](10)
+	defer statsProvider.Close()
+
+	out := channels.RateLimit(in, 10, time.Second, 1,
+		channels.ClockOption[channels.RateLimitConfig](clk),
+		channels.RateLimitModeOption(channels.DropOnRateLimit),
+		channels.ErrorProviderOption[channels.RateLimitConfig](errorProvider),
+		channels.RateLimitStatsProviderOption(statsProvider),
+		channels.ChannelCapacityOption[channels.RateLimitConfig](10),
+	)
+
+	in <- 1
+	in <- 2
+	close(in)
+
+	values, _ := channels.DrainValues(out, 0)
+	require.Equal(t, []int{1}, values)
+
+	failure := <-errorReceiver.Channel()
+	require.Equal(t, channels.RateLimitOperator, failure.Operator)
+	require.Equal(t, 2, failure.Input)
+	require.ErrorIs(t, failure.Err, channels.ErrRateLimited)
+
+	require.False(t, (<-statsReceiver.Channel()).Dropped)
+	require.True(t, (<-statsReceiver.Channel()).Dropped)
+}
+
+func TestRateLimitContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	clk := clock.NewManual(time.Now())
+	out := channels.RateLimit(in, 1, time.Hour, 1,
+		channels.ClockOption[channels.RateLimitConfig](clk),
+		channels.ContextOption[channels.RateLimitConfig](ctx),
+		channels.CancellationProviderOption[channels.RateLimitConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+
+	// stop while waiting for the rate limit
+	clk.BlockUntil(1)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
@@ -1,15 +1,26 @@
 package channels
 
 import (
+	"context"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type ReduceConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[Stats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 // Reduce reads values from the input channel and applies the provided `reduceFn` to each value.
@@ -22,25 +33,45 @@ func Reduce[TIn any, TOut any](inc <-chan TIn, reduceFn func(TOut, TIn) (TOut, b
 	cfg := parseOpts(opts...)
 
 	outc := make(chan TOut, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(ReduceOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
 		var result TOut
-		for in := range inc {
-			start := time.Now()
-			next, ok := reduceFn(result, in)
-			duration := time.Since(start)
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok {
+				return
+			}
+
+			start := clk.Now()
+			var next TOut
+			var reduced bool
+			panics.tryRecover(in, func() { next, reduced = reduceFn(result, in) })
+			duration := clk.Now().Sub(start)
 
-			if ok {
+			var sendWait time.Duration
+			if reduced {
 				result = next
-				outc <- result
+				if sendWait, ok = timedSend(ctx, clk, outc, result); !ok {
+					return
+				}
 			}
 
-			tryProvideStats(Stats{Duration: duration, QueueLength: len(inc)}, statsProvider)
+			tryProvideStats(Stats{Operator: operator, Name: name, Duration: duration, QueueLength: len(inc), SendWait: sendWait, OutputLength: len(outc)}, statsProvider)
 		}
 	}()
 
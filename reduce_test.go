@@ -1,6 +1,7 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -124,3 +125,51 @@ func TestReduceProviderOptionWithReportStats(t *testing.T) {
 	require.GreaterOrEqual(t, stats[0].Duration, 2*time.Millisecond)
 	require.Equal(t, stats[0].QueueLength, 1)
 }
+
+func TestReduceContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Reduce(in,
+		func(acc int, i int) (int, bool) { return acc + i, true },
+		channels.ContextOption[channels.ReduceConfig](ctx),
+		channels.CancellationProviderOption[channels.ReduceConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestReducePanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	result := channels.ReduceValues(in,
+		func(acc int, i int) (int, bool) {
+			if i == 2 {
+				panic("panic!")
+			}
+			return acc + i, true
+		},
+		channels.PanicRecoveryOption[channels.ReduceConfig](channels.ContinueOnPanic),
+	)
+
+	require.Equal(t, 4, result)
+}
@@ -106,3 +106,23 @@ func TestRejectProviderOptionWithReportStats(t *testing.T) {
 	require.True(t, stats[1].Selected)
 	require.Equal(t, 0, stats[1].QueueLength)
 }
+
+func TestRejectPanicInfoProviderOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.PanicInfo](1)
+	defer provider.Close()
+
+	channels.Reject(in,
+		func(i int) bool { panic("panic!") },
+		channels.PanicInfoProviderOption[channels.SelectConfig](provider),
+	)
+
+	in <- 1
+	info := <-receiver.Channel()
+	require.Equal(t, channels.RejectOperator, info.Operator)
+	require.Equal(t, "panic!", info.Value)
+}
@@ -0,0 +1,195 @@
+package channels
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+type RetryConfig struct {
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[RetryStats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	clock                clock.Clock
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	backoff              Backoff
+	retryLimit           int
+}
+
+func defaultRetryOptions() []Option[RetryConfig] {
+	return []Option[RetryConfig]{
+		RetryBackoffOption(Backoff{Initial: 100 * time.Millisecond, Max: 10 * time.Second, Multiplier: 2}),
+		RetryLimitOption(100),
+	}
+}
+
+// Backoff configures the delays between attempts made by Retry.  The delay before
+// the second attempt is Initial, and each following delay is multiplied by Multiplier,
+// up to Max when Max is positive.  Jitter randomly shortens each delay by up to the
+// given fraction, between 0 and 1, so that failed values aren't retried in lockstep.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+}
+
+// delay returns the delay before the `retry`th retry of a value.
+func (b Backoff) delay(retry int) time.Duration {
+	delay := float64(b.Initial) * math.Pow(max(b.Multiplier, 1), float64(retry-1))
+	if b.Max > 0 {
+		delay = min(delay, float64(b.Max))
+	}
+
+	if b.Jitter > 0 {
+		delay -= delay * min(b.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// Retry reads values from the input channel and calls `retryFn` with each value,
+// writing the result to the output channel when `retryFn` succeeds.  When `retryFn`
+// returns an error the value is retried, up to a total of `maxAttempts` attempts, after
+// a delay configured with RetryBackoffOption.  Retries are scheduled in their own
+// goroutines, so that values waiting to be retried don't block other values, and
+// retried values may be written out of order.  The number of values waiting to be
+// retried is limited with RetryLimitOption, once the limit is reached Retry stops
+// reading from the input channel until a value finishes retrying.  Unless panics are
+// recovered with ContinueOnPanic, a panic during a retry stops Retry in the same way
+// as a panic during the first attempt.  When every attempt fails, a Failure
+// with the last error is sent to the provider configured with ErrorProviderOption.
+// The output channel is unbuffered by default, and will be closed when the input
+// channel is closed and every value has succeeded or exhausted its attempts.
+func Retry[TIn any, TOut any](inc <-chan TIn, retryFn func(TIn) (TOut, error), maxAttempts int, opts ...Option[RetryConfig]) <-chan TOut {
+	cfg := parseOpts(append(defaultRetryOptions(), opts...)...)
+
+	outc := make(chan TOut, cfg.capacity)
+	operator := cfg.operator.or(RetryOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	errorProvider := failureProvider[TIn](cfg.errorProvider)
+	backoff := cfg.backoff
+	retries := make(chan struct{}, max(cfg.retryLimit, 1))
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	// attempt calls retryFn and reports stats for the attempt.  Returns false if a
+	// panic was recovered, in which case the value isn't retried.
+	attempt := func(in TIn, n int) (TOut, bool, error) {
+		var out TOut
+		var err error
+
+		start := clk.Now()
+		ok := panics.tryRecover(in, func() { out, err = retryFn(in) })
+		tryProvideStats(RetryStats{Operator: operator, Name: name, Attempt: n, Duration: clk.Now().Sub(start), Err: err, QueueLength: len(inc)}, statsProvider)
+
+		return out, ok, err
+	}
+
+	fail := func(in TIn, err error) {
+		tryProvideFailure(Failure[TIn]{Operator: operator, Name: name, Input: in, Err: err, Time: clk.Now()}, errorProvider)
+	}
+
+	// retries are stopped when the operation exits, and a retry that panics stops the operation
+	retryCtx, stopRetries := context.WithCancel(ctx)
+
+	retry := func(in TIn) {
+		for n := 2; n <= maxAttempts; n++ {
+			if !sleep(retryCtx, clk, backoff.delay(n-1)) {
+				return
+			}
+
+			out, ok, err := attempt(in, n)
+			if !ok {
+				return
+			}
+
+			if err == nil {
+				send(retryCtx, outc, out)
+				return
+			}
+
+			if n == maxAttempts {
+				fail(in, err)
+			}
+		}
+	}
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
+	go func() {
+		var wg sync.WaitGroup
+
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer stopRetries()
+		defer wg.Wait()
+		defer panics.handle()
+
+		for {
+			in, ok := receive(retryCtx, inc)
+			if !ok {
+				return
+			}
+
+			out, ok, err := attempt(in, 1)
+			if !ok {
+				continue
+			}
+
+			if err == nil {
+				if !send(retryCtx, outc, out) {
+					return
+				}
+				continue
+			}
+
+			if maxAttempts <= 1 {
+				fail(in, err)
+				continue
+			}
+
+			if !send(retryCtx, retries, struct{}{}) {
+				return
+			}
+
+			wg.Add(1)
+			go func(in TIn) {
+				defer wg.Done()
+				defer func() { <-retries }()
+				defer panics.handle()
+
+				// stop the operation if the retry exits from a panic
+				completed := false
+				defer func() {
+					if !completed {
+						stopRetries()
+					}
+				}()
+
+				retry(in)
+				completed = true
+			}(in)
+		}
+	}()
+
+	return outc
+}
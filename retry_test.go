@@ -0,0 +1,286 @@
+package channels_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	clk := clock.NewManual(time.Now())
+
+	provider, receiver := providers.NewProvider[channels.RetryStats](10)
+	defer provider.Close()
+
+	failed := errors.New("failed")
+	var attempts atomic.Int32
+	out := channels.Retry(in,
+		func(i int) (int, error) {
+			if i == 1 && attempts.Add(1) < 3 {
+				return 0, failed
+			}
+			return i * 10, nil
+		},
+		3,
+		channels.ClockOption[channels.RetryConfig](clk),
+		channels.RetryStatsProviderOption(provider),
+	)
+
+	in <- 1
+	in <- 2
+
+	// values waiting to be retried don't block other values
+	require.Equal(t, 20, <-out)
+
+	stats := <-receiver.Channel()
+	require.Equal(t, channels.RetryOperator, stats.Operator)
+	require.Equal(t, 1, stats.Attempt)
+	require.ErrorIs(t, stats.Err, failed)
+	stats = <-receiver.Channel()
+	require.Equal(t, 1, stats.Attempt)
+	require.NoError(t, stats.Err)
+
+	// the first retry waits for the initial backoff
+	clk.BlockUntil(1)
+	clk.Advance(100 * time.Millisecond)
+	stats = <-receiver.Channel()
+	require.Equal(t, 2, stats.Attempt)
+	require.ErrorIs(t, stats.Err, failed)
+
+	// the second retry waits twice as long
+	clk.BlockUntil(1)
+	clk.Advance(199 * time.Millisecond)
+	require.Len(t, out, 0)
+	clk.Advance(time.Millisecond)
+
+	require.Equal(t, 10, <-out)
+	stats = <-receiver.Channel()
+	require.Equal(t, 3, stats.Attempt)
+	require.NoError(t, stats.Err)
+
+	close(in)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestRetryReportsExhaustedValues(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	clk := clock.NewManual(time.Now())
+
+	provider, receiver := providers.NewProvider[channels.Failure[int]](1)
+	defer provider.Close()
+
+	out := channels.Retry(in,
+		func(i int) (int, error) { return 0, errors.New(time.Now().String()) },
+		2,
+		channels.ClockOption[channels.RetryConfig](clk),
+		channels.ErrorProviderOption[channels.RetryConfig](provider),
+		channels.RetryBackoffOption(channels.Backoff{Initial: time.Second}),
+		channels.NameOption[channels.RetryConfig]("fetch"),
+	)
+
+	in <- 1
+	close(in)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.RetryOperator, failure.Operator)
+	require.Equal(t, "fetch", failure.Name)
+	require.Equal(t, 1, failure.Input)
+	require.Error(t, failure.Err)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestRetryBackoffOptionWithMaxAndJitter(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := clock.NewManual(time.Now())
+	provider, receiver := providers.NewProvider[channels.RetryStats](10)
+	defer provider.Close()
+
+	channels.Retry(in,
+		func(i int) (int, error) { return 0, errors.New("failed") },
+		4,
+		channels.ClockOption[channels.RetryConfig](clk),
+		channels.RetryStatsProviderOption(provider),
+		channels.RetryBackoffOption(channels.Backoff{Initial: time.Second, Max: 2 * time.Second, Multiplier: 10, Jitter: 0.5}),
+	)
+
+	in <- 1
+	require.Equal(t, 1, (<-receiver.Channel()).Attempt)
+
+	// delays are between 50% and 100% of 1s, then 2s, then 2s
+	for attempt, delay := range []time.Duration{time.Second, 2 * time.Second, 2 * time.Second} {
+		clk.BlockUntil(1)
+		clk.Advance(delay / 2)
+		clk.Advance(delay / 2)
+		require.Equal(t, attempt+2, (<-receiver.Channel()).Attempt)
+	}
+}
+
+func TestRetryPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewProvider[any](1)
+	defer provider.Close()
+
+	out := channels.Retry(in,
+		func(i int) (int, error) {
+			if i == 1 {
+				panic("panic!")
+			}
+			return i, nil
+		},
+		3,
+		channels.PanicProviderOption[channels.RetryConfig](provider),
+		channels.PanicRecoveryOption[channels.RetryConfig](channels.ContinueOnPanic),
+	)
+
+	in <- 1
+	in <- 2
+	close(in)
+
+	require.Equal(t, 2, <-out)
+	recovered := (<-receiver.Channel()).(channels.RecoveredPanic)
+	require.Equal(t, 1, recovered.Input)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestRetryPanicDuringRetryStopsRetry(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+	clk := clock.NewManual(time.Now())
+
+	provider, receiver := providers.NewProvider[any](1)
+	defer provider.Close()
+
+	var attempts atomic.Int32
+	out := channels.Retry(in,
+		func(i int) (int, error) {
+			if attempts.Add(1) == 2 {
+				panic("panic!")
+			}
+			return 0, errors.New("failed")
+		},
+		3,
+		channels.ClockOption[channels.RetryConfig](clk),
+		channels.PanicProviderOption[channels.RetryConfig](provider),
+	)
+
+	in <- 1
+
+	clk.BlockUntil(1)
+	clk.Advance(100 * time.Millisecond)
+	require.Equal(t, "panic!", <-receiver.Channel())
+
+	// the panic stops Retry without reading the remaining values
+	_, ok := <-out
+	require.False(t, ok)
+
+	in <- 2
+	require.Len(t, in, 1)
+}
+
+func TestRetryLimitOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	clk := clock.NewManual(time.Now())
+
+	failed := errors.New("failed")
+	var retried sync.Map
+	out := channels.Retry(in,
+		func(i int) (int, error) {
+			// every value fails its first attempt
+			if _, loaded := retried.LoadOrStore(i, true); !loaded {
+				return 0, failed
+			}
+			return i, nil
+		},
+		2,
+		channels.ClockOption[channels.RetryConfig](clk),
+		channels.RetryLimitOption(1),
+	)
+
+	in <- 1
+	in <- 2
+	in <- 3
+
+	// 1 is waiting to be retried, so 2 waits for the retry of 1 to finish and 3 isn't read
+	clk.BlockUntil(1)
+	require.Eventually(t, func() bool { return len(in) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	require.Len(t, in, 1)
+
+	clk.Advance(100 * time.Millisecond)
+	require.Equal(t, 1, <-out)
+
+	clk.BlockUntil(1)
+	clk.Advance(100 * time.Millisecond)
+	require.Equal(t, 2, <-out)
+
+	clk.BlockUntil(1)
+	clk.Advance(100 * time.Millisecond)
+	require.Equal(t, 3, <-out)
+
+	close(in)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestRetryContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	clk := clock.NewManual(time.Now())
+	out := channels.Retry(in,
+		func(i int) (int, error) { return 0, errors.New("failed") },
+		3,
+		channels.ClockOption[channels.RetryConfig](clk),
+		channels.ContextOption[channels.RetryConfig](ctx),
+		channels.CancellationProviderOption[channels.RetryConfig](provider),
+	)
+
+	in <- 1
+
+	// stop while waiting to retry
+	clk.BlockUntil(1)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
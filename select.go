@@ -1,15 +1,29 @@
 package channels
 
 import (
+	"context"
 	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type SelectConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[SelectStats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[SelectStats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	workers              int
+	order                OutputOrder
+	errorProvider        any
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 // Selects values from the input channel that return true from the provided `selectFn`
@@ -19,24 +33,42 @@ func Select[T any](inc <-chan T, selectFn func(T) bool, opts ...Option[SelectCon
 	cfg := parseOpts(opts...)
 
 	outc := make(chan T, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(SelectOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+	workers := cfg.workers
+	order := cfg.order
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
-		for in := range inc {
-			start := time.Now()
-			selected := selectFn(in)
-			duration := time.Since(start)
+		processConcurrently(ctx, inc, workers, order, clk, panics,
+			func(in T) (T, bool) { return in, selectFn(in) },
+			func(ctx context.Context, result workResult[T, T]) bool {
+				var sendWait time.Duration
+				outputLength := 0
+				if result.ok {
+					var ok bool
+					if sendWait, ok = timedSend(ctx, clk, outc, result.out); !ok {
+						return false
+					}
+					outputLength = len(outc)
+				}
 
-			if selected {
-				outc <- in
-			}
-
-			tryProvideStats(SelectStats{Duration: duration, Selected: selected, QueueLength: len(inc)}, statsProvider)
-		}
+				tryProvideStats(SelectStats{Operator: operator, Name: name, Duration: result.duration, Selected: result.ok, QueueLength: len(inc), BusyWorkers: result.busy, SendWait: sendWait, OutputLength: outputLength}, statsProvider)
+				return true
+			},
+		)
 	}()
 
 	return outc
@@ -54,3 +86,23 @@ func SelectValues[T any](inc <-chan T, selectFn func(T) bool, opts ...Option[Sel
 
 	return result
 }
+
+// SelectErr is like Select, but with a `selectFn` that can fail.  When `selectFn` returns an
+// error the value is not selected, and a Failure describing the input value and error is sent
+// to the provider configured with ErrorProviderOption.
+func SelectErr[T any](inc <-chan T, selectFn func(T) (bool, error), opts ...Option[SelectConfig]) <-chan T {
+	cfg := parseOpts(opts...)
+	errorProvider := failureProvider[T](cfg.errorProvider)
+	name := cfg.name
+	clk := clockOrReal(cfg.clock)
+
+	return Select(inc, func(in T) bool {
+		selected, err := selectFn(in)
+		if err != nil {
+			tryProvideFailure(Failure[T]{Operator: SelectErrOperator, Name: name, Input: in, Err: err, Time: clk.Now()}, errorProvider)
+			return false
+		}
+
+		return selected
+	}, withOperator(SelectErrOperator, opts)...)
+}
@@ -1,12 +1,15 @@
 package channels_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
@@ -106,3 +109,124 @@ func TestSelectProviderOptionWithReportStats(t *testing.T) {
 	require.True(t, stats[1].Selected)
 	require.Equal(t, 0, stats[1].QueueLength)
 }
+
+func TestSelectStatsReportSendWait(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := &steppingClock{Manual: clock.NewManual(time.Now()), step: time.Second}
+	provider, receiver := providers.NewProvider[channels.SelectStats](1)
+	defer provider.Close()
+
+	out := channels.Select(in,
+		func(i int) bool { return true },
+		channels.ChannelCapacityOption[channels.SelectConfig](1),
+		channels.ClockOption[channels.SelectConfig](clk),
+		channels.SelectStatsProviderOption(provider),
+	)
+
+	in <- 1
+	stats := <-receiver.Channel()
+	require.Equal(t, time.Second, stats.SendWait)
+	require.Equal(t, 1, stats.OutputLength)
+	require.Equal(t, 1, <-out)
+}
+func TestSelectContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Select(in,
+		func(i int) bool { return true },
+		channels.ContextOption[channels.SelectConfig](ctx),
+		channels.CancellationProviderOption[channels.SelectConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestSelectConcurrencyOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 10)
+	for i := 0; i < cap(in); i++ {
+		in <- i
+	}
+	close(in)
+
+	out := channels.SelectValues(in,
+		func(i int) bool { return i%2 == 0 },
+		channels.ConcurrencyOption[channels.SelectConfig](3, channels.UnorderedOutput),
+	)
+
+	require.ElementsMatch(t, []int{0, 2, 4, 6, 8}, out)
+}
+
+func TestSelectErr(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewProvider[channels.Failure[int]](1)
+	defer provider.Close()
+
+	failed := errors.New("negative")
+	out := channels.SelectErr(in,
+		func(i int) (bool, error) {
+			if i < 0 {
+				return true, failed
+			}
+			return i%2 == 0, nil
+		},
+		channels.ErrorProviderOption[channels.SelectConfig](provider),
+	)
+
+	in <- -2
+	in <- 1
+	in <- 2
+	close(in)
+
+	require.Equal(t, 2, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.SelectErrOperator, failure.Operator)
+	require.Equal(t, -2, failure.Input)
+	require.ErrorIs(t, failure.Err, failed)
+}
+
+func TestSelectErrNameOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.Failure[int]](1)
+	defer provider.Close()
+
+	channels.SelectErr(in,
+		func(i int) (bool, error) { return false, errors.New("failed") },
+		channels.NameOption[channels.SelectConfig]("validate"),
+		channels.ErrorProviderOption[channels.SelectConfig](provider),
+	)
+
+	in <- 1
+	failure := <-receiver.Channel()
+	require.Equal(t, channels.SelectErrOperator, failure.Operator)
+	require.Equal(t, "validate", failure.Name)
+}
@@ -1,7 +1,15 @@
 package channels
 
+import (
+	"context"
+
+	"github.com/jonabc/channels/providers"
+)
+
 type SignalConfig struct {
-	capacity int
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
 }
 
 // WithDone returns two channels: a channel containing piped input
@@ -13,13 +21,19 @@ func WithDone[T any](inc <-chan T, opts ...Option[SignalConfig]) (<-chan T, <-ch
 
 	outc := make(chan T, cfg.capacity)
 	signal := make(chan struct{})
+	cancellationProvider := cfg.cancellationProvider
+	ctx := contextOrBackground(cfg.ctx)
 
 	go func() {
 		defer close(signal)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
 
-		for in := range inc {
-			outc <- in
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok || !send(ctx, outc, in) {
+				return
+			}
 		}
 	}()
 
@@ -1,6 +1,7 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/jonabc/channels"
@@ -53,3 +54,24 @@ func TestWithDoneChannelCapacityOption(t *testing.T) {
 
 	require.Equal(t, 5, cap(out))
 }
+
+func TestWithDoneContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out, done := channels.WithDone(in,
+		channels.ContextOption[channels.SignalConfig](ctx),
+	)
+
+	in <- 1
+	cancel()
+
+	<-done
+	for range out {
+		// a value may have been in flight when the context was cancelled
+	}
+}
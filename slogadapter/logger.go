@@ -0,0 +1,246 @@
+// Package slogadapter logs panics, failures, lifecycle events and stats anomalies
+// reported by channels functions to a log/slog Logger.
+package slogadapter
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+)
+
+// Config contains user configurable options for a Logger.
+type Config struct {
+	panicLevel           slog.Level
+	failureLevel         slog.Level
+	lifecycleLevel       slog.Level
+	anomalyLevel         slog.Level
+	queueLengthThreshold int
+	durationThreshold    time.Duration
+}
+
+type Option func(*Config)
+
+// Specify the level of records logged for panics.  The default level is slog.LevelError.
+func PanicLevelOption(level slog.Level) Option {
+	return func(cfg *Config) {
+		cfg.panicLevel = level
+	}
+}
+
+// Specify the level of records logged for failures, including values dropped by
+// channels functions.  The default level is slog.LevelWarn.
+func FailureLevelOption(level slog.Level) Option {
+	return func(cfg *Config) {
+		cfg.failureLevel = level
+	}
+}
+
+// Specify the level of records logged when channels functions start and stop.
+// The default level is slog.LevelDebug.
+func LifecycleLevelOption(level slog.Level) Option {
+	return func(cfg *Config) {
+		cfg.lifecycleLevel = level
+	}
+}
+
+// Specify the level of records logged for stats anomalies.  The default level is
+// slog.LevelWarn.
+func AnomalyLevelOption(level slog.Level) Option {
+	return func(cfg *Config) {
+		cfg.anomalyLevel = level
+	}
+}
+
+// Specify the input channel length above which stats are logged as an anomaly.
+// Queue lengths are not checked by default.
+func QueueLengthThresholdOption(threshold int) Option {
+	return func(cfg *Config) {
+		cfg.queueLengthThreshold = threshold
+	}
+}
+
+// Specify the operation duration above which stats are logged as an anomaly.
+// Durations are not checked by default.
+func DurationThresholdOption(threshold time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.durationThreshold = threshold
+	}
+}
+
+// Logger writes reports from channels functions to a slog.Logger.  Pass a provider
+// returned from the logger, e.g. PanicInfoProvider or LifecycleProvider, to the
+// matching option of a channels function.  Records include "operator" and "name"
+// attributes identifying the function that made the report, see channels.NameOption.
+type Logger struct {
+	logger *slog.Logger
+	cfg    Config
+}
+
+// New returns a Logger that writes records to `logger`.
+func New(logger *slog.Logger, opts ...Option) *Logger {
+	cfg := Config{
+		panicLevel:     slog.LevelError,
+		failureLevel:   slog.LevelWarn,
+		lifecycleLevel: slog.LevelDebug,
+		anomalyLevel:   slog.LevelWarn,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Logger{logger: logger, cfg: cfg}
+}
+
+// Returns a provider that logs values passed to channels.PanicProviderOption.
+// Panic values don't identify the channels function that panicked, see
+// PanicInfoProvider to log panics with operator attributes.  Closing the returned
+// provider has no effect.
+func (l *Logger) PanicProvider() providers.Provider[any] {
+	return &logProvider[any]{log: func(p any) {
+		if recovered, ok := p.(channels.RecoveredPanic); ok {
+			l.log(l.cfg.panicLevel, "channels function panicked",
+				slog.Any("panic", recovered.Value),
+				slog.Any("input", recovered.Input),
+				slog.String("stack", string(recovered.Stack)),
+			)
+			return
+		}
+
+		l.log(l.cfg.panicLevel, "channels function panicked", slog.Any("panic", p))
+	}}
+}
+
+// Returns a provider that logs PanicInfo.  Closing the returned provider has no effect.
+func (l *Logger) PanicInfoProvider() providers.Provider[channels.PanicInfo] {
+	return &logProvider[channels.PanicInfo]{log: func(p channels.PanicInfo) {
+		attrs := append(operatorAttrs(p.Operator, p.Name),
+			slog.Any("panic", p.Value),
+			slog.String("stack", string(p.Stack)),
+		)
+		if p.Input != nil {
+			attrs = append(attrs, slog.Any("input", p.Input))
+		}
+
+		l.log(l.cfg.panicLevel, "channels function panicked", attrs...)
+	}}
+}
+
+// Returns a provider that logs Failures for `T` values, including values dropped or
+// rejected by channels functions, to the logger.  FailureProvider is a function rather
+// than a Logger method because methods can't have type parameters.  Closing the
+// returned provider has no effect.
+func FailureProvider[T any](l *Logger) providers.Provider[channels.Failure[T]] {
+	return &logProvider[channels.Failure[T]]{log: func(f channels.Failure[T]) {
+		l.log(l.cfg.failureLevel, "channels function failed",
+			append(operatorAttrs(f.Operator, f.Name), slog.Any("error", f.Err), slog.Any("input", f.Input))...,
+		)
+	}}
+}
+
+// Returns a provider that logs LifecycleEvents.  Closing the returned provider has no effect.
+func (l *Logger) LifecycleProvider() providers.Provider[channels.LifecycleEvent] {
+	return &logProvider[channels.LifecycleEvent]{log: func(e channels.LifecycleEvent) {
+		attrs := operatorAttrs(e.Operator, e.Name)
+		if e.Err != nil {
+			attrs = append(attrs, slog.Any("error", e.Err))
+		}
+
+		l.log(l.cfg.lifecycleLevel, "channels function "+e.State.String(), attrs...)
+	}}
+}
+
+// Returns a provider that logs Stats with a queue length or duration above the
+// configured thresholds.  Closing the returned provider has no effect.
+func (l *Logger) StatsProvider() providers.Provider[channels.Stats] {
+	return &logProvider[channels.Stats]{log: func(s channels.Stats) {
+		l.checkQueueLength(s.Operator, s.Name, s.QueueLength)
+		l.checkDuration(s.Operator, s.Name, s.Duration)
+	}}
+}
+
+// Returns a provider that logs BatchStats with a queue length or duration above the
+// configured thresholds.  Closing the returned provider has no effect.
+func (l *Logger) BatchStatsProvider() providers.Provider[channels.BatchStats] {
+	return &logProvider[channels.BatchStats]{log: func(s channels.BatchStats) {
+		l.checkQueueLength(s.Operator, s.Name, s.QueueLength)
+		l.checkDuration(s.Operator, s.Name, s.Duration)
+	}}
+}
+
+// Returns a provider that logs RateLimitStats for dropped values, or with a queue
+// length or wait above the configured thresholds.  Closing the returned provider
+// has no effect.
+func (l *Logger) RateLimitStatsProvider() providers.Provider[channels.RateLimitStats] {
+	return &logProvider[channels.RateLimitStats]{log: func(s channels.RateLimitStats) {
+		if s.Dropped {
+			l.log(l.cfg.anomalyLevel, "channels function dropped a value", operatorAttrs(s.Operator, s.Name)...)
+		}
+		l.checkQueueLength(s.Operator, s.Name, s.QueueLength)
+		l.checkDuration(s.Operator, s.Name, s.Wait)
+	}}
+}
+
+// Returns a provider that logs SelectStats with a queue length or duration above the
+// configured thresholds.  Closing the returned provider has no effect.
+func (l *Logger) SelectStatsProvider() providers.Provider[channels.SelectStats] {
+	return &logProvider[channels.SelectStats]{log: func(s channels.SelectStats) {
+		l.checkQueueLength(s.Operator, s.Name, s.QueueLength)
+		l.checkDuration(s.Operator, s.Name, s.Duration)
+	}}
+}
+
+// Returns a provider that logs TapStats with a queue length, or combined pre and post
+// duration, above the configured thresholds.  Closing the returned provider has no effect.
+func (l *Logger) TapStatsProvider() providers.Provider[channels.TapStats] {
+	return &logProvider[channels.TapStats]{log: func(s channels.TapStats) {
+		l.checkQueueLength(s.Operator, s.Name, s.QueueLength)
+		l.checkDuration(s.Operator, s.Name, s.PreDuration+s.PostDuration)
+	}}
+}
+
+func (l *Logger) checkQueueLength(operator channels.OperatorKind, name string, queueLength int) {
+	if l.cfg.queueLengthThreshold <= 0 || queueLength <= l.cfg.queueLengthThreshold {
+		return
+	}
+
+	l.log(l.cfg.anomalyLevel, "channels function queue length above threshold",
+		append(operatorAttrs(operator, name), slog.Int("queue_length", queueLength), slog.Int("threshold", l.cfg.queueLengthThreshold))...,
+	)
+}
+
+func (l *Logger) checkDuration(operator channels.OperatorKind, name string, duration time.Duration) {
+	if l.cfg.durationThreshold <= 0 || duration <= l.cfg.durationThreshold {
+		return
+	}
+
+	l.log(l.cfg.anomalyLevel, "channels function duration above threshold",
+		append(operatorAttrs(operator, name), slog.Duration("duration", duration), slog.Duration("threshold", l.cfg.durationThreshold))...,
+	)
+}
+
+func (l *Logger) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	l.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func operatorAttrs(operator channels.OperatorKind, name string) []slog.Attr {
+	return []slog.Attr{slog.String("operator", string(operator)), slog.String("name", name)}
+}
+
+// logProvider adapts a Logger to a provider for a single report type.
+type logProvider[T any] struct {
+	log func(T)
+}
+
+func (p *logProvider[T]) IsClosed() bool {
+	return false
+}
+
+func (p *logProvider[T]) Close() {}
+
+func (p *logProvider[T]) Provide(val T) bool {
+	p.log(val)
+	return true
+}
@@ -0,0 +1,178 @@
+package slogadapter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/slogadapter"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects the JSON records written by a slog handler.
+type recorder struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.Write(p)
+}
+
+func (r *recorder) records(t *testing.T) []map[string]any {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records := []map[string]any{}
+	for _, line := range bytes.Split(bytes.TrimSpace(r.buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		record := map[string]any{}
+		require.NoError(t, json.Unmarshal(line, &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func newLogger(opts ...slogadapter.Option) (*slogadapter.Logger, *recorder) {
+	rec := &recorder{}
+	handler := slog.NewJSONHandler(rec, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "stack" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	return slogadapter.New(slog.New(handler), opts...), rec
+}
+
+func TestLoggerPanicProviders(t *testing.T) {
+	t.Parallel()
+
+	logger, rec := newLogger()
+
+	require.True(t, logger.PanicProvider().Provide("boom"))
+	require.True(t, logger.PanicProvider().Provide(channels.RecoveredPanic{Value: "boom", Input: 1}))
+	require.True(t, logger.PanicInfoProvider().Provide(channels.PanicInfo{Operator: channels.MapOperator, Name: "parse", Value: "boom"}))
+
+	require.Equal(t, []map[string]any{
+		{"level": "ERROR", "msg": "channels function panicked", "panic": "boom"},
+		{"level": "ERROR", "msg": "channels function panicked", "panic": "boom", "input": float64(1)},
+		{"level": "ERROR", "msg": "channels function panicked", "operator": "Map", "name": "parse", "panic": "boom"},
+	}, rec.records(t))
+}
+
+func TestLoggerFailureProvider(t *testing.T) {
+	t.Parallel()
+
+	logger, rec := newLogger(slogadapter.FailureLevelOption(slog.LevelInfo))
+
+	provider := slogadapter.FailureProvider[string](logger)
+	require.False(t, provider.IsClosed())
+	require.True(t, provider.Provide(channels.Failure[string]{Operator: channels.RateLimitOperator, Name: "limit", Input: "a", Err: channels.ErrRateLimited}))
+
+	require.Equal(t, []map[string]any{
+		{"level": "INFO", "msg": "channels function failed", "operator": "RateLimit", "name": "limit", "error": "rate limit exceeded", "input": "a"},
+	}, rec.records(t))
+}
+
+func TestLoggerLifecycleProvider(t *testing.T) {
+	t.Parallel()
+
+	logger, rec := newLogger()
+
+	provider := logger.LifecycleProvider()
+	require.True(t, provider.Provide(channels.LifecycleEvent{Operator: channels.TapOperator, Name: "log", State: channels.OperatorStarted}))
+	require.True(t, provider.Provide(channels.LifecycleEvent{Operator: channels.TapOperator, Name: "log", State: channels.OperatorStopped, Err: errors.New("shutdown")}))
+
+	require.Equal(t, []map[string]any{
+		{"level": "DEBUG", "msg": "channels function started", "operator": "Tap", "name": "log"},
+		{"level": "DEBUG", "msg": "channels function stopped", "operator": "Tap", "name": "log", "error": "shutdown"},
+	}, rec.records(t))
+}
+
+func TestLoggerStatsAnomalies(t *testing.T) {
+	t.Parallel()
+
+	logger, rec := newLogger(
+		slogadapter.AnomalyLevelOption(slog.LevelError),
+		slogadapter.QueueLengthThresholdOption(10),
+		slogadapter.DurationThresholdOption(time.Second),
+	)
+
+	stats := logger.StatsProvider()
+	require.True(t, stats.Provide(channels.Stats{Operator: channels.MapOperator, Name: "parse", Duration: time.Second, QueueLength: 10}))
+	require.True(t, stats.Provide(channels.Stats{Operator: channels.MapOperator, Name: "parse", Duration: 2 * time.Second, QueueLength: 11}))
+
+	rateLimitStats := logger.RateLimitStatsProvider()
+	require.True(t, rateLimitStats.Provide(channels.RateLimitStats{Operator: channels.RateLimitOperator, Dropped: true}))
+
+	require.Equal(t, []map[string]any{
+		{"level": "ERROR", "msg": "channels function queue length above threshold", "operator": "Map", "name": "parse", "queue_length": float64(11), "threshold": float64(10)},
+		{"level": "ERROR", "msg": "channels function duration above threshold", "operator": "Map", "name": "parse", "duration": float64(2 * time.Second), "threshold": float64(time.Second)},
+		{"level": "ERROR", "msg": "channels function dropped a value", "operator": "RateLimit", "name": ""},
+	}, rec.records(t))
+}
+
+func TestLoggerWithoutThresholds(t *testing.T) {
+	t.Parallel()
+
+	logger, rec := newLogger()
+
+	require.True(t, logger.BatchStatsProvider().Provide(channels.BatchStats{Duration: time.Hour, QueueLength: 1000}))
+	require.True(t, logger.SelectStatsProvider().Provide(channels.SelectStats{Duration: time.Hour, QueueLength: 1000}))
+	require.True(t, logger.TapStatsProvider().Provide(channels.TapStats{PreDuration: time.Hour, QueueLength: 1000}))
+
+	require.Empty(t, rec.records(t))
+}
+
+func TestLoggerWithChannelsFunction(t *testing.T) {
+	t.Parallel()
+
+	logger, rec := newLogger()
+
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	close(in)
+
+	out := channels.Map(in,
+		func(i int) (int, bool) {
+			if i == 1 {
+				panic("boom")
+			}
+			return i, true
+		},
+		channels.NameOption[channels.MapConfig]("parse"),
+		channels.PanicRecoveryOption[channels.MapConfig](channels.ContinueOnPanic),
+		channels.PanicInfoProviderOption[channels.MapConfig](logger.PanicInfoProvider()),
+		channels.LifecycleProviderOption[channels.MapConfig](logger.LifecycleProvider()),
+	)
+
+	require.Equal(t, 2, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+
+	require.Eventually(t, func() bool { return len(rec.records(t)) == 3 }, time.Second, time.Millisecond)
+	require.Equal(t, []map[string]any{
+		{"level": "DEBUG", "msg": "channels function started", "operator": "Map", "name": "parse"},
+		{"level": "ERROR", "msg": "channels function panicked", "operator": "Map", "name": "parse", "panic": "boom", "input": float64(1)},
+		{"level": "DEBUG", "msg": "channels function stopped", "operator": "Map", "name": "parse"},
+	}, rec.records(t))
+}
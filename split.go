@@ -1,16 +1,26 @@
 package channels
 
 import (
+	"context"
 	"sync"
-	"time"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type SplitConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[Stats]
-	capacities    []int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[Stats]
+	capacities           []int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 func defaultSplitOptions(count int) []Option[SplitConfig] {
@@ -30,14 +40,22 @@ func defaultSplitOptions(count int) []Option[SplitConfig] {
 // that chans[0] will hold even values and chans[1] will hold odd values.
 
 // Each output channel is unbuffered by default, and will be closed after the
-// input channel is closed and emptied.
+// input channel is closed and emptied.  Writes made by `splitFn` are not
+// interrupted by a context passed with `ContextOption`, `splitFn` should
+// avoid blocking writes when the context may be cancelled.
 func Split[T any](inc <-chan T, count int, splitFn func(T, []chan<- T), opts ...Option[SplitConfig]) []<-chan T {
 	cfg := parseOpts(append(defaultSplitOptions(count), opts...)...)
 
 	writeOutc := make([]chan<- T, count)
 	readOutc := make([]<-chan T, count)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(SplitOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
 	for i := 0; i < count; i++ {
 		c := make(chan T, cfg.capacities[i])
@@ -45,19 +63,33 @@ func Split[T any](inc <-chan T, count int, splitFn func(T, []chan<- T), opts ...
 		readOutc[i] = c
 	}
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer func() {
 			for _, c := range writeOutc {
 				close(c)
 			}
 		}()
+		defer panics.handle()
 
-		for in := range inc {
-			start := time.Now()
-			splitFn(in, writeOutc)
-			duration := time.Since(start)
-			tryProvideStats(Stats{Duration: duration, QueueLength: len(inc)}, statsProvider)
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok {
+				return
+			}
+
+			start := clk.Now()
+			panics.tryRecover(in, func() { splitFn(in, writeOutc) })
+			duration := clk.Now().Sub(start)
+
+			outputLength := 0
+			for _, c := range writeOutc {
+				outputLength += len(c)
+			}
+			tryProvideStats(Stats{Operator: operator, Name: name, Duration: duration, QueueLength: len(inc), OutputLength: outputLength}, statsProvider)
 		}
 	}()
 
@@ -1,6 +1,7 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -172,3 +173,113 @@ func TestSplitProviderOptionWithReportStats(t *testing.T) {
 	require.GreaterOrEqual(t, stats[0].Duration, 2*time.Millisecond)
 	require.Equal(t, stats[0].QueueLength, 1)
 }
+
+func TestSplitStatsReportOutputLength(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.Stats](2)
+	defer provider.Close()
+
+	out := channels.Split(in,
+		2,
+		func(i int, chans []chan<- int) { chans[i%2] <- i },
+		channels.StatsProviderOption[channels.SplitConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+
+	require.Equal(t, 1, (<-receiver.Channel()).OutputLength)
+	require.Equal(t, 2, (<-receiver.Channel()).OutputLength)
+	require.Equal(t, 1, <-out[1])
+	require.Equal(t, 2, <-out[0])
+}
+
+func TestSplitContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Split(in, 2,
+		func(i int, chans []chan<- int) { chans[i%2] <- i },
+		channels.ContextOption[channels.SplitConfig](ctx),
+		channels.CancellationProviderOption[channels.SplitConfig](provider),
+	)
+
+	cancel()
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+
+	for _, c := range out {
+		_, ok := <-c
+		require.False(t, ok)
+	}
+}
+
+func TestSplitContextOptionWithFullOutput(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	written := make(chan int, 100)
+	out := channels.Split(in, 2,
+		func(i int, chans []chan<- int) {
+			// splitFn sees the output channel's capacity, and drops values when it's full
+			if len(chans[0]) < cap(chans[0]) {
+				chans[0] <- i
+			}
+			written <- i
+		},
+		channels.MultiChannelCapacitiesOption[channels.SplitConfig]([]int{1, 1}),
+		channels.ContextOption[channels.SplitConfig](ctx),
+		channels.CancellationProviderOption[channels.SplitConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-written)
+	require.Equal(t, 2, <-written)
+
+	cancel()
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+
+	require.Equal(t, 1, <-out[0])
+	for _, c := range out {
+		_, ok := <-c
+		require.False(t, ok)
+	}
+}
+
+func TestSplitPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	out := channels.SplitValues(in, 2,
+		func(i int, chans []chan<- int) {
+			if i == 2 {
+				panic("panic!")
+			}
+			chans[i%2] <- i
+		},
+		channels.PanicRecoveryOption[channels.SplitConfig](channels.ContinueOnPanic),
+	)
+
+	require.Equal(t, [][]int{{}, {1, 3}}, out)
+}
@@ -6,43 +6,118 @@ import (
 	"github.com/jonabc/channels/providers"
 )
 
-// Stats provides an operation duration.
+// Stats provides an operation duration.  BusyWorkers is the number of
+// workers processing values at the start of the operation, and is only
+// set by functions that accept ConcurrencyOption.  SendWait is the time spent
+// blocked writing to the output channel, and OutputLength is the length of the
+// output channel after the write.  Both are set by Map, FlatMap and Reduce.  Split
+// sets OutputLength to the total length of its output channels, but not SendWait:
+// its `splitFn` writes to the output channels directly, so the time spent writing
+// is included in Duration.
 type Stats struct {
-	Duration    time.Duration
-	QueueLength int
+	Operator     OperatorKind
+	Name         string
+	Duration     time.Duration
+	QueueLength  int
+	BusyWorkers  int
+	SendWait     time.Duration
+	OutputLength int
 }
 
-// BatchStats provides a batch operation's duration and batch size.
+// BatchStats provides a batch operation's duration and batch size.  SendWait is
+// the time spent blocked writing the batch to the output channel, and OutputLength
+// is the length of the output channel after the write.
 type BatchStats struct {
+	Operator     OperatorKind
+	Name         string
+	Duration     time.Duration
+	BatchSize    uint
+	QueueLength  int
+	SendWait     time.Duration
+	OutputLength int
+}
+
+// BroadcastStats provides the time taken to write a value to every output
+// channel, and the number of values dropped so far by each output channel.
+type BroadcastStats struct {
+	Operator    OperatorKind
+	Name        string
 	Duration    time.Duration
-	BatchSize   uint
+	Dropped     []uint
 	QueueLength int
 }
 
 // DebounceStats provides a debounce operation's delay and debounced count.
 type DebounceStats struct {
+	Operator    OperatorKind
+	Name        string
 	Delay       time.Duration
 	Count       uint
 	QueueLength int
 }
 
 // SelectStats provides a select or reject operation's duration and
-// whether the item was selected or not.
+// whether the item was selected or not.  SendWait and OutputLength are
+// only set for selected items.
 type SelectStats struct {
-	Duration    time.Duration
-	Selected    bool
-	QueueLength int
+	Operator     OperatorKind
+	Name         string
+	Duration     time.Duration
+	Selected     bool
+	QueueLength  int
+	BusyWorkers  int
+	SendWait     time.Duration
+	OutputLength int
 }
 
 // TapStats provides the duration of a tap operations pre and post functions.
+// SendWait is the time spent blocked writing the value to the output channel,
+// and OutputLength is the length of the output channel after the write.
 type TapStats struct {
+	Operator     OperatorKind
+	Name         string
 	PreDuration  time.Duration
 	PostDuration time.Duration
 	QueueLength  int
+	SendWait     time.Duration
+	OutputLength int
+}
+
+// MergeStats provides the input channel that a merged value was read from.
+// Input is the index of the input channel, and Count is the number of values
+// read from that input channel so far.
+type MergeStats struct {
+	Operator    OperatorKind
+	Name        string
+	Input       int
+	Count       uint
+	QueueLength int
+}
+
+// RateLimitStats provides how long a value waited for a rate limit, and
+// whether the value was dropped.  Wait doesn't include the time spent blocked
+// writing the value to the output channel.
+type RateLimitStats struct {
+	Operator    OperatorKind
+	Name        string
+	Wait        time.Duration
+	Dropped     bool
+	QueueLength int
+}
+
+// RetryStats provides the duration and error of a single attempt made by Retry.
+// Attempt starts at 1 for the first attempt with each value.
+type RetryStats struct {
+	Operator    OperatorKind
+	Name        string
+	Attempt     int
+	Duration    time.Duration
+	Err         error
+	QueueLength int
 }
 
 type statsProviderInput interface {
-	Stats | BatchStats | DebounceStats | SelectStats | TapStats
+	Stats | BatchStats | BroadcastStats | DebounceStats | MergeStats | RateLimitStats | RetryStats | SelectStats | TapStats
 }
 
 func tryProvideStats[T statsProviderInput](stats T, provider providers.Provider[T]) {
@@ -0,0 +1,427 @@
+package channels
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+// statsSampleSize is the maximum number of durations sampled per operator in each
+// interval to estimate percentiles.
+const statsSampleSize = 1024
+
+// StatsSummary summarizes the stats reported by a channels function from Start to End.
+// Durations are the Duration of each stats value, or the time spent waiting for
+// stats types without a Duration, e.g. the Delay of DebounceStats and the Wait of
+// RateLimitStats.  Percentiles are estimated from a sample of durations.
+// SelectionRatio is only set from SelectStats, and MeanBatchSize is only set from
+// BatchStats.  Dropped counts values dropped by RateLimit, and Errors counts failed
+// Retry attempts.
+type StatsSummary struct {
+	Operator        OperatorKind
+	Name            string
+	Start           time.Time
+	End             time.Time
+	Count           uint64
+	Rate            float64
+	MinDuration     time.Duration
+	MaxDuration     time.Duration
+	MeanDuration    time.Duration
+	P50Duration     time.Duration
+	P95Duration     time.Duration
+	P99Duration     time.Duration
+	SelectionRatio  float64
+	MeanBatchSize   float64
+	MeanQueueLength float64
+	MaxQueueLength  int
+	Dropped         uint64
+	Errors          uint64
+}
+
+type StatsAggregatorConfig struct {
+	panicProvider        providers.Provider[any]
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	clock                clock.Clock
+	panicInfoProvider    providers.Provider[PanicInfo]
+	name                 string
+}
+
+// StatsAggregatorOption configures a StatsAggregator.  A StatsAggregator isn't a
+// channels function, so it has its own options instead of accepting Option.
+type StatsAggregatorOption func(*StatsAggregatorConfig)
+
+// Specify a panic provider for the aggregator, see PanicProviderOption.
+func StatsAggregatorPanicProviderOption(provider providers.Provider[any]) StatsAggregatorOption {
+	return func(cfg *StatsAggregatorConfig) {
+		cfg.panicProvider = provider
+	}
+}
+
+// Specify a provider receiving structured panic reports from the aggregator, see
+// PanicInfoProviderOption.
+func StatsAggregatorPanicInfoProviderOption(provider providers.Provider[PanicInfo]) StatsAggregatorOption {
+	return func(cfg *StatsAggregatorConfig) {
+		cfg.panicInfoProvider = provider
+	}
+}
+
+// Specify a context that stops the aggregator when it's done.
+func StatsAggregatorContextOption(ctx context.Context) StatsAggregatorOption {
+	return func(cfg *StatsAggregatorConfig) {
+		cfg.ctx = ctx
+	}
+}
+
+// Specify a provider receiving the cause of the aggregator's context being done,
+// see CancellationProviderOption.
+func StatsAggregatorCancellationProviderOption(provider providers.Provider[error]) StatsAggregatorOption {
+	return func(cfg *StatsAggregatorConfig) {
+		cfg.cancellationProvider = provider
+	}
+}
+
+// Specify the clock used to time the aggregator's intervals, see ClockOption.
+func StatsAggregatorClockOption(c clock.Clock) StatsAggregatorOption {
+	return func(cfg *StatsAggregatorConfig) {
+		cfg.clock = c
+	}
+}
+
+// Specify the name reported in the aggregator's panic reports, see NameOption.
+func StatsAggregatorNameOption(name string) StatsAggregatorOption {
+	return func(cfg *StatsAggregatorConfig) {
+		cfg.name = name
+	}
+}
+
+// StatsAggregator aggregates stats reported by channels functions, and periodically
+// writes a StatsSummary for each reporting function to a provider.  Pass a provider
+// returned from the aggregator, e.g. StatsProvider or BatchStatsProvider, to the stats
+// provider option of a channels function.  Stats are summarized per operator and
+// name, see NameOption to summarize functions with the same operator separately.
+type StatsAggregator struct {
+	mu        sync.Mutex
+	windows   map[statsKey]*statsWindow
+	start     time.Time
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+type statsKey struct {
+	operator OperatorKind
+	name     string
+}
+
+// statsRecord is the aggregated information from a single stats value.
+type statsRecord struct {
+	key         statsKey
+	duration    time.Duration
+	hasDuration bool
+	queueLength int
+	batchSize   uint
+	selected    bool
+	dropped     bool
+	failed      bool
+}
+
+type statsWindow struct {
+	count         uint64
+	selected      uint64
+	batchTotal    uint64
+	queueTotal    uint64
+	queueMax      int
+	dropped       uint64
+	errors        uint64
+	durations     uint64
+	durationTotal time.Duration
+	durationMin   time.Duration
+	durationMax   time.Duration
+	sample        []time.Duration
+}
+
+// NewStatsAggregator returns a StatsAggregator that writes a StatsSummary to `provider`
+// every `interval` duration for each channels function that reported stats during the
+// interval.  The aggregator runs until Close is called or the context passed with
+// StatsAggregatorContextOption is done, after which summaries of any remaining stats
+// are written.  NewStatsAggregator panics if `interval` isn't positive.
+func NewStatsAggregator(interval time.Duration, provider providers.Provider[StatsSummary], opts ...StatsAggregatorOption) *StatsAggregator {
+	if interval <= 0 {
+		panic("channels: non-positive interval for NewStatsAggregator")
+	}
+
+	cfg := parseOpts(opts...)
+
+	panics := newPanicHandler(StatsAggregatorOperator, cfg.name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	cancellationProvider := cfg.cancellationProvider
+	ctx := contextOrBackground(cfg.ctx)
+	clk := clockOrReal(cfg.clock)
+
+	aggregator := &StatsAggregator{
+		windows: make(map[statsKey]*statsWindow),
+		start:   clk.Now(),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	ticker := clk.NewTicker(interval)
+
+	publish := func() {
+		for _, summary := range aggregator.flush(clk.Now()) {
+			provider.Provide(summary)
+		}
+	}
+
+	go func() {
+		defer close(aggregator.stopped)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer ticker.Stop()
+		defer panics.handle()
+
+		for {
+			select {
+			case <-ctx.Done():
+				aggregator.signalDone()
+				publish()
+				return
+			case <-aggregator.done:
+				publish()
+				return
+			case <-ticker.C():
+				publish()
+			}
+		}
+	}()
+
+	return aggregator
+}
+
+// Returns true if the aggregator has been closed, false otherwise
+func (a *StatsAggregator) IsClosed() bool {
+	select {
+	case <-a.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close the aggregator, writing summaries of any remaining stats.  Close blocks
+// until the summaries have been written.
+func (a *StatsAggregator) Close() {
+	a.signalDone()
+	<-a.stopped
+}
+
+func (a *StatsAggregator) signalDone() {
+	a.closeOnce.Do(func() { close(a.done) })
+}
+
+// Returns a provider that aggregates Stats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) StatsProvider() providers.Provider[Stats] {
+	return &aggregatorProvider[Stats]{aggregator: a, toRecord: func(s Stats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Duration, hasDuration: true, queueLength: s.QueueLength}
+	}}
+}
+
+// Returns a provider that aggregates BatchStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) BatchStatsProvider() providers.Provider[BatchStats] {
+	return &aggregatorProvider[BatchStats]{aggregator: a, toRecord: func(s BatchStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Duration, hasDuration: true, queueLength: s.QueueLength, batchSize: s.BatchSize}
+	}}
+}
+
+// Returns a provider that aggregates BroadcastStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) BroadcastStatsProvider() providers.Provider[BroadcastStats] {
+	return &aggregatorProvider[BroadcastStats]{aggregator: a, toRecord: func(s BroadcastStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Duration, hasDuration: true, queueLength: s.QueueLength}
+	}}
+}
+
+// Returns a provider that aggregates DebounceStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) DebounceStatsProvider() providers.Provider[DebounceStats] {
+	return &aggregatorProvider[DebounceStats]{aggregator: a, toRecord: func(s DebounceStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Delay, hasDuration: true, queueLength: s.QueueLength}
+	}}
+}
+
+// Returns a provider that aggregates MergeStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) MergeStatsProvider() providers.Provider[MergeStats] {
+	return &aggregatorProvider[MergeStats]{aggregator: a, toRecord: func(s MergeStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, queueLength: s.QueueLength}
+	}}
+}
+
+// Returns a provider that aggregates RateLimitStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) RateLimitStatsProvider() providers.Provider[RateLimitStats] {
+	return &aggregatorProvider[RateLimitStats]{aggregator: a, toRecord: func(s RateLimitStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Wait, hasDuration: !s.Dropped, queueLength: s.QueueLength, dropped: s.Dropped}
+	}}
+}
+
+// Returns a provider that aggregates RetryStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) RetryStatsProvider() providers.Provider[RetryStats] {
+	return &aggregatorProvider[RetryStats]{aggregator: a, toRecord: func(s RetryStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Duration, hasDuration: true, queueLength: s.QueueLength, failed: s.Err != nil}
+	}}
+}
+
+// Returns a provider that aggregates SelectStats.  Closing the returned provider
+// has no effect, close the aggregator instead.
+func (a *StatsAggregator) SelectStatsProvider() providers.Provider[SelectStats] {
+	return &aggregatorProvider[SelectStats]{aggregator: a, toRecord: func(s SelectStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.Duration, hasDuration: true, queueLength: s.QueueLength, selected: s.Selected}
+	}}
+}
+
+// Returns a provider that aggregates TapStats, using the sum of PreDuration and
+// PostDuration as the duration.  Closing the returned provider has no effect,
+// close the aggregator instead.
+func (a *StatsAggregator) TapStatsProvider() providers.Provider[TapStats] {
+	return &aggregatorProvider[TapStats]{aggregator: a, toRecord: func(s TapStats) statsRecord {
+		return statsRecord{key: statsKey{s.Operator, s.Name}, duration: s.PreDuration + s.PostDuration, hasDuration: true, queueLength: s.QueueLength}
+	}}
+}
+
+func (a *StatsAggregator) record(record statsRecord) bool {
+	if a.IsClosed() {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	window, ok := a.windows[record.key]
+	if !ok {
+		window = &statsWindow{}
+		a.windows[record.key] = window
+	}
+
+	window.count++
+	window.queueTotal += uint64(max(record.queueLength, 0))
+	window.queueMax = max(window.queueMax, record.queueLength)
+	window.batchTotal += uint64(record.batchSize)
+	if record.selected {
+		window.selected++
+	}
+	if record.dropped {
+		window.dropped++
+	}
+	if record.failed {
+		window.errors++
+	}
+
+	if !record.hasDuration {
+		return true
+	}
+
+	window.durations++
+	window.durationTotal += record.duration
+	if window.durations == 1 || record.duration < window.durationMin {
+		window.durationMin = record.duration
+	}
+	window.durationMax = max(window.durationMax, record.duration)
+
+	// reservoir sample durations to bound memory use at high volumes
+	if len(window.sample) < statsSampleSize {
+		window.sample = append(window.sample, record.duration)
+	} else if i := rand.Int63n(int64(window.durations)); i < statsSampleSize {
+		window.sample[i] = record.duration
+	}
+
+	return true
+}
+
+// flush returns summaries of the stats recorded since the last flush, ordered by
+// operator and name, and resets the recorded stats.
+func (a *StatsAggregator) flush(end time.Time) []StatsSummary {
+	a.mu.Lock()
+	windows := a.windows
+	start := a.start
+	a.windows = make(map[statsKey]*statsWindow)
+	a.start = end
+	a.mu.Unlock()
+
+	summaries := make([]StatsSummary, 0, len(windows))
+	for key, window := range windows {
+		summaries = append(summaries, window.summarize(key, start, end))
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Operator != summaries[j].Operator {
+			return summaries[i].Operator < summaries[j].Operator
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries
+}
+
+func (w *statsWindow) summarize(key statsKey, start time.Time, end time.Time) StatsSummary {
+	summary := StatsSummary{
+		Operator:        key.operator,
+		Name:            key.name,
+		Start:           start,
+		End:             end,
+		Count:           w.count,
+		SelectionRatio:  float64(w.selected) / float64(w.count),
+		MeanBatchSize:   float64(w.batchTotal) / float64(w.count),
+		MeanQueueLength: float64(w.queueTotal) / float64(w.count),
+		MaxQueueLength:  w.queueMax,
+		Dropped:         w.dropped,
+		Errors:          w.errors,
+	}
+
+	if elapsed := end.Sub(start); elapsed > 0 {
+		summary.Rate = float64(w.count) / elapsed.Seconds()
+	}
+
+	if w.durations > 0 {
+		sort.Slice(w.sample, func(i, j int) bool { return w.sample[i] < w.sample[j] })
+
+		summary.MinDuration = w.durationMin
+		summary.MaxDuration = w.durationMax
+		summary.MeanDuration = w.durationTotal / time.Duration(w.durations)
+		summary.P50Duration = percentile(w.sample, 0.50)
+		summary.P95Duration = percentile(w.sample, 0.95)
+		summary.P99Duration = percentile(w.sample, 0.99)
+	}
+
+	return summary
+}
+
+// percentile returns the nearest-rank percentile `p` of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[min(max(rank-1, 0), len(sorted)-1)]
+}
+
+// aggregatorProvider adapts a StatsAggregator to a provider for a single stats type.
+type aggregatorProvider[T any] struct {
+	aggregator *StatsAggregator
+	toRecord   func(T) statsRecord
+}
+
+func (p *aggregatorProvider[T]) IsClosed() bool {
+	return p.aggregator.IsClosed()
+}
+
+func (p *aggregatorProvider[T]) Close() {}
+
+func (p *aggregatorProvider[T]) Provide(stats T) bool {
+	return p.aggregator.record(p.toRecord(stats))
+}
@@ -0,0 +1,102 @@
+package channels_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsAggregator(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Now())
+	provider, receiver := providers.NewProvider[channels.StatsSummary](10)
+	defer provider.Close()
+
+	aggregator := channels.NewStatsAggregator(time.Second, provider,
+		channels.StatsAggregatorClockOption(clk),
+	)
+	defer aggregator.Close()
+
+	selectStats := aggregator.SelectStatsProvider()
+	for i := 1; i <= 100; i++ {
+		require.True(t, selectStats.Provide(channels.SelectStats{
+			Operator:    channels.SelectOperator,
+			Duration:    time.Duration(i) * time.Millisecond,
+			Selected:    i%4 == 0,
+			QueueLength: i % 3,
+		}))
+	}
+
+	batchStats := aggregator.BatchStatsProvider()
+	require.True(t, batchStats.Provide(channels.BatchStats{Operator: channels.BatchOperator, Name: "batches", Duration: time.Second, BatchSize: 10}))
+	require.True(t, batchStats.Provide(channels.BatchStats{Operator: channels.BatchOperator, Name: "batches", Duration: time.Second, BatchSize: 20}))
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	summary := <-receiver.Channel()
+	require.Equal(t, channels.BatchOperator, summary.Operator)
+	require.Equal(t, "batches", summary.Name)
+	require.Equal(t, uint64(2), summary.Count)
+	require.Equal(t, 15.0, summary.MeanBatchSize)
+	require.Equal(t, 2.0, summary.Rate)
+
+	summary = <-receiver.Channel()
+	require.Equal(t, channels.SelectOperator, summary.Operator)
+	require.Equal(t, uint64(100), summary.Count)
+	require.Equal(t, 100.0, summary.Rate)
+	require.Equal(t, time.Millisecond, summary.MinDuration)
+	require.Equal(t, 100*time.Millisecond, summary.MaxDuration)
+	require.Equal(t, 50500*time.Microsecond, summary.MeanDuration)
+	require.Equal(t, 50*time.Millisecond, summary.P50Duration)
+	require.Equal(t, 95*time.Millisecond, summary.P95Duration)
+	require.Equal(t, 99*time.Millisecond, summary.P99Duration)
+	require.Equal(t, 0.25, summary.SelectionRatio)
+	require.Equal(t, 1.0, summary.MeanQueueLength)
+	require.Equal(t, 2, summary.MaxQueueLength)
+	require.Equal(t, summary.Start.Add(time.Second), summary.End)
+}
+
+func TestStatsAggregatorCloseWritesRemainingStats(t *testing.T) {
+	t.Parallel()
+
+	provider, receiver := providers.NewProvider[channels.StatsSummary](10)
+	defer provider.Close()
+
+	aggregator := channels.NewStatsAggregator(time.Hour, provider)
+
+	inc := make(chan int)
+	out := channels.Map(inc, func(i int) (int, bool) { return i, true },
+		channels.StatsProviderOption[channels.MapConfig](aggregator.StatsProvider()),
+	)
+
+	inc <- 1
+	<-out
+	inc <- 2
+	<-out
+	close(inc)
+	for range out {
+	}
+
+	aggregator.Close()
+	require.True(t, aggregator.IsClosed())
+	require.False(t, aggregator.StatsProvider().Provide(channels.Stats{}))
+
+	summary := <-receiver.Channel()
+	require.Equal(t, channels.MapOperator, summary.Operator)
+	require.Equal(t, uint64(2), summary.Count)
+}
+
+func TestStatsAggregatorPanicsWithNonPositiveInterval(t *testing.T) {
+	t.Parallel()
+
+	provider, _ := providers.NewProvider[channels.StatsSummary](1)
+	defer provider.Close()
+
+	require.Panics(t, func() { channels.NewStatsAggregator(0, provider) })
+}
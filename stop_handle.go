@@ -0,0 +1,149 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrStopped is the cause of the context of channels functions stopped with StopHandle.Stop.
+var ErrStopped = errors.New("stopped")
+
+type StopMode byte
+
+const (
+	// Stopped functions stop reading from their input channels, leaving any unread
+	// values in the input channels.
+	DiscardInputOnStop StopMode = iota
+	// Stopped functions read and discard values from their input channels until the
+	// input channels are closed, so that writers to the input channels aren't blocked.
+	DrainInputOnStop
+)
+
+// StopHandle stops channels functions whose consumers stop reading from their output
+// channels, and reports when the functions have exited.  Pass a StopHandle to channels
+// functions with StopHandleOption.  A StopHandle can be shared by the functions in a
+// pipeline, in which case Stop stops every function and Done is closed once every
+// function has exited.  Functions created with a handle after its Done channel is
+// closed are stopped immediately, and aren't waited for.
+type StopHandle struct {
+	mode   StopMode
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu      sync.Mutex
+	stopped bool
+	active  int
+	done    chan struct{}
+}
+
+// NewStopHandle returns a StopHandle that stops functions according to `mode`.
+func NewStopHandle(mode StopMode) *StopHandle {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &StopHandle{mode: mode, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+}
+
+// Stop stops the functions using the handle.  Stopped functions abandon any pending
+// writes and close their output channels, and the cause of their context is ErrStopped.
+// Stop can be called more than once, and before the functions have started.
+func (h *StopHandle) Stop() {
+	h.cancel(ErrStopped)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	h.closeIfDone()
+}
+
+// Done returns a channel that is closed once Stop has been called and every function
+// using the handle has exited, including any goroutines started by the functions and,
+// with DrainInputOnStop, once their input channels are drained.  Functions that exit
+// on their own before Stop is called don't close Done.
+func (h *StopHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// bind returns a context that is done when `parent` is done or the handle is stopped,
+// and registers a function using the handle.  The returned func must be called when
+// the function has exited.  bind returns `parent` when the handle is nil.
+func (h *StopHandle) bind(parent context.Context) (context.Context, func()) {
+	if h == nil {
+		return parent, func() {}
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.isDone() {
+		// Done is already closed, so the function can't be waited for
+		cancel(ErrStopped)
+		return ctx, func() {}
+	}
+	h.active++
+
+	stop := context.AfterFunc(h.ctx, func() { cancel(context.Cause(h.ctx)) })
+	if h.ctx.Err() != nil {
+		// AfterFunc calls its func in a new goroutine, make sure the function
+		// doesn't start processing values when the handle is already stopped
+		cancel(context.Cause(h.ctx))
+	}
+
+	return ctx, func() {
+		stop()
+		cancel(nil)
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.active--
+		h.closeIfDone()
+	}
+}
+
+// closeIfDone closes the done channel once the handle is stopped and every function
+// using the handle has exited.  The handle's mutex must be held.
+func (h *StopHandle) closeIfDone() {
+	if !h.stopped || h.active > 0 || h.isDone() {
+		return
+	}
+
+	close(h.done)
+}
+
+// isDone returns true if the done channel is closed.  The handle's mutex must be held.
+func (h *StopHandle) isDone() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// cause returns ErrStopped if the handle was stopped, and nil otherwise.
+func (h *StopHandle) cause() error {
+	if h == nil {
+		return nil
+	}
+
+	return context.Cause(h.ctx)
+}
+
+// draining returns true if the handle was stopped with DrainInputOnStop.
+func (h *StopHandle) draining() bool {
+	return h != nil && h.mode == DrainInputOnStop && h.ctx.Err() != nil
+}
+
+// drainStopped reads and discards values from the input channel until it's closed,
+// if the handle was stopped with DrainInputOnStop.
+func drainStopped[T any](h *StopHandle, inc <-chan T) {
+	if !h.draining() {
+		return
+	}
+
+	for range inc {
+	}
+}
@@ -0,0 +1,203 @@
+package channels_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopHandle(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	handle := channels.NewStopHandle(channels.DiscardInputOnStop)
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.StopHandleOption[channels.MapConfig](handle),
+		channels.CancellationProviderOption[channels.MapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	in <- 3
+	require.Equal(t, 1, <-out)
+
+	// the consumer goes away while Map is waiting to write 2
+	require.Eventually(t, func() bool { return len(in) == 1 }, time.Second, time.Millisecond)
+	handle.Stop()
+	handle.Stop()
+
+	<-handle.Done()
+	require.ErrorIs(t, <-receiver.Channel(), channels.ErrStopped)
+	for range out {
+		// 2 may have been in flight when the handle was stopped
+	}
+
+	// unread values are left in the input channel
+	require.Len(t, in, 1)
+}
+
+func TestStopHandleDrainInputOnStop(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+
+	handle := channels.NewStopHandle(channels.DrainInputOnStop)
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.StopHandleOption[channels.MapConfig](handle),
+	)
+
+	in <- 1
+	handle.Stop()
+
+	for range out {
+		// 1 may have been in flight when the handle was stopped
+	}
+
+	// writes to the input channel don't block after the handle is stopped
+	for i := 0; i < 10; i++ {
+		in <- i
+	}
+
+	select {
+	case <-handle.Done():
+		require.Fail(t, "done before the input channel was closed")
+	default:
+	}
+
+	close(in)
+	<-handle.Done()
+}
+
+func TestStopHandleWithPipeline(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	handle := channels.NewStopHandle(channels.DiscardInputOnStop)
+	lifecycleProvider, lifecycleReceiver := providers.NewProvider[channels.LifecycleEvent](4)
+	defer lifecycleProvider.Close()
+
+	mapped := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.StopHandleOption[channels.MapConfig](handle),
+		channels.LifecycleProviderOption[channels.MapConfig](lifecycleProvider),
+	)
+	debounced, _ := channels.Debounce(mapped, time.Millisecond,
+		channels.StopHandleOption[channels.DebounceConfig](handle),
+		channels.LifecycleProviderOption[channels.DebounceConfig](lifecycleProvider),
+	)
+	merged := channels.Merge([]<-chan int{debounced}, channels.StopHandleOption[channels.MergeConfig](handle))
+
+	in <- 1
+	require.Equal(t, 1, <-merged)
+	in <- 2
+
+	handle.Stop()
+	<-handle.Done()
+
+	for range merged {
+		// 2 may have been in flight when the handle was stopped
+	}
+
+	events := []channels.LifecycleEvent{}
+	for i := 0; i < 4; i++ {
+		events = append(events, <-lifecycleReceiver.Channel())
+	}
+
+	stopped := map[channels.OperatorKind]error{}
+	for _, event := range events {
+		if event.State == channels.OperatorStopped {
+			stopped[event.Operator] = event.Err
+		}
+	}
+	require.Equal(t, map[channels.OperatorKind]error{
+		channels.MapOperator:      channels.ErrStopped,
+		channels.DebounceOperator: channels.ErrStopped,
+	}, stopped)
+}
+
+func TestStopHandleStoppedBeforeStart(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 1)
+	defer close(in)
+	in <- 1
+
+	handle := channels.NewStopHandle(channels.DiscardInputOnStop)
+	handle.Stop()
+
+	out := channels.Tap(in, nil, nil, channels.StopHandleOption[channels.TapConfig](handle))
+
+	_, ok := <-out
+	require.False(t, ok)
+	<-handle.Done()
+	require.Len(t, in, 1)
+}
+
+func TestStopHandleDoneWaitsForStop(t *testing.T) {
+	t.Parallel()
+
+	first := make(chan int)
+	second := make(chan int)
+	defer close(second)
+
+	handle := channels.NewStopHandle(channels.DiscardInputOnStop)
+	firstOut := channels.Map(first,
+		func(i int) (int, bool) { return i, true },
+		channels.StopHandleOption[channels.MapConfig](handle),
+	)
+
+	// the only function using the handle exits before the next one is created
+	close(first)
+	_, ok := <-firstOut
+	require.False(t, ok)
+
+	secondOut := channels.Map(second,
+		func(i int) (int, bool) { return i, true },
+		channels.StopHandleOption[channels.MapConfig](handle),
+	)
+
+	select {
+	case <-handle.Done():
+		require.Fail(t, "done before the handle was stopped")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	handle.Stop()
+	<-handle.Done()
+	_, ok = <-secondOut
+	require.False(t, ok)
+}
+
+func TestStopHandleWithoutFunctions(t *testing.T) {
+	t.Parallel()
+
+	handle := channels.NewStopHandle(channels.DiscardInputOnStop)
+	handle.Stop()
+	<-handle.Done()
+
+	// functions created after the handle is done are stopped immediately
+	in := make(chan int, 1)
+	defer close(in)
+	in <- 1
+
+	out := channels.Map(in,
+		func(i int) (int, bool) { return i, true },
+		channels.StopHandleOption[channels.MapConfig](handle),
+	)
+
+	_, ok := <-out
+	require.False(t, ok)
+	require.Len(t, in, 1)
+}
@@ -1,15 +1,25 @@
 package channels
 
 import (
-	"time"
+	"context"
 
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 )
 
 type TapConfig struct {
-	panicProvider providers.Provider[any]
-	statsProvider providers.Provider[TapStats]
-	capacity      int
+	panicProvider        providers.Provider[any]
+	statsProvider        providers.Provider[TapStats]
+	capacity             int
+	ctx                  context.Context
+	cancellationProvider providers.Provider[error]
+	panicRecovery        PanicRecovery
+	panicInfoProvider    providers.Provider[PanicInfo]
+	operator             OperatorKind
+	name                 string
+	lifecycleProvider    providers.Provider[LifecycleEvent]
+	stopHandle           *StopHandle
+	clock                clock.Clock
 }
 
 // Tap reads values from the input channel and calls the provided
@@ -21,29 +31,48 @@ func Tap[T any](inc <-chan T, preFn, postFn func(T), opts ...Option[TapConfig])
 	cfg := parseOpts(opts...)
 
 	outc := make(chan T, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(TapOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, cfg.panicRecovery, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
+		defer panics.handle()
 
-		for val := range inc {
-			start := time.Now()
-			if preFn != nil {
-				preFn(val)
+		for {
+			val, ok := receive(ctx, inc)
+			if !ok {
+				return
 			}
-			preDuration := time.Since(start)
 
-			outc <- val
+			start := clk.Now()
+			if preFn != nil && !panics.tryRecover(val, func() { preFn(val) }) {
+				continue
+			}
+			preDuration := clk.Now().Sub(start)
+
+			sendWait, ok := timedSend(ctx, clk, outc, val)
+			if !ok {
+				return
+			}
+			outputLength := len(outc)
 
-			start = time.Now()
-			if postFn != nil {
-				postFn(val)
+			start = clk.Now()
+			if postFn != nil && !panics.tryRecover(val, func() { postFn(val) }) {
+				continue
 			}
-			postDuration := time.Since(start)
+			postDuration := clk.Now().Sub(start)
 
-			tryProvideStats(TapStats{PreDuration: preDuration, PostDuration: postDuration, QueueLength: len(inc)}, statsProvider)
+			tryProvideStats(TapStats{Operator: operator, Name: name, PreDuration: preDuration, PostDuration: postDuration, QueueLength: len(inc), SendWait: sendWait, OutputLength: outputLength}, statsProvider)
 		}
 	}()
 
@@ -1,10 +1,12 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
 	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
@@ -73,6 +75,37 @@ func TestTapProviderOptionWithReportPanics(t *testing.T) {
 	require.Equal(t, "panic!", <-receiver.Channel())
 }
 
+func TestTapReportsPanicsBeforeClosingOutput(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[any](0)
+	defer provider.Close()
+
+	out := channels.Tap(in,
+		func(i int) { panic("panic!") },
+		nil,
+		channels.PanicProviderOption[channels.TapConfig](provider),
+	)
+
+	in <- 1
+
+	require.Never(t, func() bool {
+		select {
+		case <-out:
+			return true
+		default:
+			return false
+		}
+	}, 20*time.Millisecond, time.Millisecond)
+
+	require.Equal(t, "panic!", <-receiver.Channel())
+	_, ok := <-out
+	require.False(t, ok)
+}
+
 func TestTapProviderOptionWithReportStats(t *testing.T) {
 	t.Parallel()
 
@@ -100,3 +133,90 @@ func TestTapProviderOptionWithReportStats(t *testing.T) {
 	require.GreaterOrEqual(t, stats[0].PostDuration, 4*time.Millisecond)
 	require.Equal(t, 1, stats[0].QueueLength)
 }
+
+func TestTapStatsReportSendWait(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	clk := &steppingClock{Manual: clock.NewManual(time.Now()), step: time.Second}
+	provider, receiver := providers.NewProvider[channels.TapStats](1)
+	defer provider.Close()
+
+	out := channels.Tap(in, nil, nil,
+		channels.ChannelCapacityOption[channels.TapConfig](1),
+		channels.ClockOption[channels.TapConfig](clk),
+		channels.TapStatsProviderOption(provider),
+	)
+
+	in <- 1
+	stats := <-receiver.Channel()
+	require.Equal(t, time.Second, stats.SendWait)
+	require.Equal(t, 1, stats.OutputLength)
+	require.Equal(t, 1, <-out)
+}
+func TestTapContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.Tap(in, nil, nil,
+		channels.ContextOption[channels.TapConfig](ctx),
+		channels.CancellationProviderOption[channels.TapConfig](provider),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, 1, <-out)
+	cancel()
+
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestTapPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+
+	provider, receiver := providers.NewCollectingProvider[any](0)
+	defer provider.Close()
+
+	out := channels.Tap(in,
+		func(i int) {
+			if i == 1 {
+				panic("pre")
+			}
+		},
+		func(i int) {
+			if i == 2 {
+				panic("post")
+			}
+		},
+		channels.PanicProviderOption[channels.TapConfig](provider),
+		channels.PanicRecoveryOption[channels.TapConfig](channels.ContinueOnPanic),
+	)
+
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	// a panic in preFn skips the value, a panic in postFn happens after the value is written
+	require.Equal(t, 2, <-out)
+	require.Equal(t, 3, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+
+	panics := <-receiver.Channel()
+	require.Len(t, panics, 2)
+	require.Equal(t, "pre", panics[0].(channels.RecoveredPanic).Value)
+	require.Equal(t, "post", panics[1].(channels.RecoveredPanic).Value)
+}
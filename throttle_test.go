@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/providers"
 	"github.com/stretchr/testify/require"
 )
 
@@ -109,3 +110,26 @@ func TestThrottleCustom(t *testing.T) {
 	require.Less(t, time.Since(start), delay)
 	require.Equal(t, 2, getThrottledCount())
 }
+
+func TestThrottleCustomPanicInfoProviderOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan *panickingDebouncingType, 100)
+	defer close(in)
+
+	provider, receiver := providers.NewProvider[channels.PanicInfo](1)
+	defer provider.Close()
+
+	channels.ThrottleCustom(in,
+		channels.PanicInfoProviderOption[channels.DebounceConfig](provider),
+		channels.PanicRecoveryOption[channels.DebounceConfig](channels.ContinueOnPanic),
+	)
+
+	input := &panickingDebouncingType{customDebouncingType: customDebouncingType{key: "1"}, panicOnDelay: true}
+	in <- input
+
+	info := <-receiver.Channel()
+	require.Equal(t, channels.ThrottleCustomOperator, info.Operator)
+	require.Equal(t, "delay", info.Value)
+	require.Equal(t, input, info.Input)
+}
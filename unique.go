@@ -23,19 +23,31 @@ func Unique[T comparable](inc <-chan T, batchSize int, maxDelay time.Duration, o
 	cfg := parseOpts(opts...)
 
 	outc := make(chan []T, cfg.capacity)
+	// the bridging goroutines are stopped by the stop handle along with the wrapped function
+	ctx, release := cfg.stopHandle.bind(contextOrBackground(cfg.ctx))
+	inDone := make(chan struct{})
 
 	inBridge := make(chan *keyedWrapper[T])
 	go func() {
+		defer close(inDone)
+		defer drainStopped(cfg.stopHandle, inc)
 		defer close(inBridge)
-		for in := range inc {
-			inBridge <- &keyedWrapper[T]{val: in}
+		for {
+			in, ok := receive(ctx, inc)
+			if !ok || !send(ctx, inBridge, &keyedWrapper[T]{val: in}) {
+				return
+			}
 		}
 	}()
 
 	outBridge := UniqueKeyed(inBridge, batchSize, maxDelay,
-		append(opts, ChannelCapacityOption[BatchConfig](0))...,
+		append(withOperator(UniqueOperator, opts), ChannelCapacityOption[BatchConfig](0))...,
 	)
 	go func() {
+		defer func() {
+			<-inDone
+			release()
+		}()
 		defer close(outc)
 		for out := range outBridge {
 			vals := make([]T, len(out))
@@ -43,7 +55,9 @@ func Unique[T comparable](inc <-chan T, batchSize int, maxDelay time.Duration, o
 				vals[i] = wrapper.val
 			}
 
-			outc <- vals
+			if !send(ctx, outc, vals) {
+				return
+			}
 		}
 	}()
 
@@ -59,12 +73,18 @@ func UniqueKeyed[K comparable, V Keyable[K]](inc <-chan V, batchSize int, maxDel
 	cfg := parseOpts(opts...)
 
 	outc := make(chan []V, cfg.capacity)
-	panicProvider := cfg.panicProvider
+	operator := cfg.operator.or(UniqueKeyedOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
 	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
 
 	buffer := make(map[K]V, batchSize)
 
-	timer := internalTime.NewTimer(maxDelay)
+	timer := internalTime.NewTimer(clk, maxDelay)
 	timer.Stop()
 
 	var batchStart time.Time
@@ -75,22 +95,33 @@ func UniqueKeyed[K comparable, V Keyable[K]](inc <-chan V, batchSize int, maxDel
 			return
 		}
 
-		duration := time.Since(batchStart)
+		duration := clk.Now().Sub(batchStart)
 		batchSize := len(buffer)
 
 		keys := maps.Values(buffer)
-		outc <- keys
 		clear(buffer)
-		tryProvideStats(BatchStats{Duration: duration, BatchSize: uint(batchSize), QueueLength: len(inc)}, statsProvider)
+		if sendWait, ok := timedSend(ctx, clk, outc, keys); ok {
+			tryProvideStats(BatchStats{Operator: operator, Name: name, Duration: duration, BatchSize: uint(batchSize), QueueLength: len(inc), SendWait: sendWait, OutputLength: len(outc)}, statsProvider)
+		}
 	}
 
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
 	go func() {
-		defer tryHandlePanic(panicProvider)
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
 		defer close(outc)
 		defer timer.Stop()
+		defer panics.handle()
 
 		for {
+			if ctx.Err() != nil {
+				return
+			}
+
 			select {
+			case <-ctx.Done():
+				return
 			case in, ok := <-inc:
 				if !ok {
 					publishAndReset()
@@ -99,7 +130,7 @@ func UniqueKeyed[K comparable, V Keyable[K]](inc <-chan V, batchSize int, maxDel
 
 				if len(buffer) == 0 {
 					timer.Reset(maxDelay)
-					batchStart = time.Now()
+					batchStart = clk.Now()
 				}
 
 				buffer[in.Key()] = in
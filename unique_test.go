@@ -1,6 +1,7 @@
 package channels_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -192,3 +193,25 @@ func TestUniqueProviderOptionWithStatsReporting(t *testing.T) {
 	require.Equal(t, uint(2), stats[0].BatchSize)
 	require.Equal(t, 0, stats[0].QueueLength)
 }
+
+func TestUniqueContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 100)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out := channels.Unique(in, 1, 0,
+		channels.ContextOption[channels.BatchConfig](ctx),
+	)
+
+	in <- 1
+	in <- 2
+	require.Equal(t, []int{1}, <-out)
+	cancel()
+
+	for range out {
+		// a value may have been in flight when the context was cancelled
+	}
+}
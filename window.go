@@ -0,0 +1,151 @@
+package channels
+
+import (
+	"time"
+)
+
+// Window contains the values read from an input channel from the window's Start
+// time, inclusive, to its End time, exclusive.
+type Window[T any] struct {
+	Start  time.Time
+	End    time.Time
+	Values []T
+}
+
+type windowValue[T any] struct {
+	val  T
+	time time.Time
+}
+
+// TumblingWindow groups values from the input channel into consecutive, non-overlapping windows
+// of `size` duration, aligned to multiples of `size` since the zero time.  For example, with a size
+// of 10 seconds, windows start at 10 second boundaries of the clock.  A window is written to the
+// output channel when it ends.  The output channel is unbuffered by default, and will be closed
+// when the input channel is closed and drained.  If the current window contains values when the
+// input channel is closed, the partial window will be sent to the output channel.
+// Windows without values are only written when EmptyWindowsOption is set.
+// TumblingWindow panics if `size` is not positive.
+func TumblingWindow[T any](inc <-chan T, size time.Duration, opts ...Option[BatchConfig]) <-chan Window[T] {
+	return window(inc, size, size, TumblingWindowOperator, opts)
+}
+
+// SlidingWindow groups values from the input channel into overlapping windows of `size` duration.
+// A window ends every `slide` duration, aligned to multiples of `slide` since the zero time, and each
+// window contains the values read in the `size` duration before it ended.  A value is included in
+// every window that it was read during.  The output channel is unbuffered by default, and will be
+// closed when the input channel is closed and drained.  If values that haven't been written in a
+// window are buffered when the input channel is closed, the window ending at the next `slide`
+// boundary will be sent to the output channel.
+// Windows without values are only written when EmptyWindowsOption is set.
+// SlidingWindow panics if `size` or `slide` is not positive.
+func SlidingWindow[T any](inc <-chan T, size time.Duration, slide time.Duration, opts ...Option[BatchConfig]) <-chan Window[T] {
+	return window(inc, size, slide, SlidingWindowOperator, opts)
+}
+
+func window[T any](inc <-chan T, size time.Duration, slide time.Duration, defaultOperator OperatorKind, opts []Option[BatchConfig]) <-chan Window[T] {
+	if size <= 0 || slide <= 0 {
+		panic("non-positive duration for window")
+	}
+
+	cfg := parseOpts(opts...)
+
+	outc := make(chan Window[T], cfg.capacity)
+	operator := cfg.operator.or(defaultOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	statsProvider := cfg.statsProvider
+	cancellationProvider := cfg.cancellationProvider
+	emitEmpty := cfg.emitEmpty
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+	clk := clockOrReal(cfg.clock)
+
+	// values are buffered until they have been included in every window they were read during
+	buffer := []windowValue[T]{}
+
+	now := clk.Now()
+	end := now.Truncate(slide).Add(slide)
+	timer := clk.NewTimer(end.Sub(now))
+
+	// publish writes the window ending at `end`, and drops values that won't be included
+	// in the next window.  Returns false if the context is done before the window is written.
+	publish := func() bool {
+		start := end.Add(-size)
+		values := []T{}
+		for _, buffered := range buffer {
+			if !buffered.time.Before(start) && buffered.time.Before(end) {
+				values = append(values, buffered.val)
+			}
+		}
+
+		nextStart := end.Add(slide - size)
+		dropped := 0
+		for dropped < len(buffer) && buffer[dropped].time.Before(nextStart) {
+			dropped++
+		}
+		buffer = buffer[dropped:]
+
+		if len(values) == 0 && !emitEmpty {
+			return true
+		}
+
+		duration := clk.Now().Sub(start)
+		sendWait, ok := timedSend(ctx, clk, outc, Window[T]{Start: start, End: end, Values: values})
+		if !ok {
+			return false
+		}
+
+		tryProvideStats(BatchStats{Operator: operator, Name: name, Duration: duration, BatchSize: uint(len(values)), QueueLength: len(inc), SendWait: sendWait, OutputLength: len(outc)}, statsProvider)
+		return true
+	}
+
+	drainOnStop(lifecycle, inc)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer timer.Stop()
+		defer panics.handle()
+
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case in, ok := <-inc:
+				if !ok {
+					if len(buffer) > 0 {
+						publish()
+					}
+					return
+				}
+
+				// values read after a window ends but before it's written are included in the window
+				now := clk.Now()
+				if !now.Before(end) {
+					now = end.Add(-time.Nanosecond)
+				}
+				buffer = append(buffer, windowValue[T]{val: in, time: now})
+			case <-timer.C():
+				// write every window that has ended, in case the timer fired late
+				for {
+					if !publish() {
+						return
+					}
+
+					end = end.Add(slide)
+					if now := clk.Now(); now.Before(end) {
+						timer.Reset(end.Sub(now))
+						break
+					}
+				}
+			}
+		}
+	}()
+
+	return outc
+}
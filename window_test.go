@@ -0,0 +1,216 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonabc/channels"
+	"github.com/jonabc/channels/clock"
+	"github.com/jonabc/channels/providers"
+)
+
+var windowTestStart = time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC)
+
+func windowTestTime(seconds int) time.Time {
+	return windowTestStart.Truncate(time.Minute).Add(time.Duration(seconds) * time.Second)
+}
+
+func TestTumblingWindow(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	clk := clock.NewManual(windowTestStart)
+
+	provider, receiver := providers.NewProvider[channels.BatchStats](1)
+	defer provider.Close()
+
+	out := channels.TumblingWindow(in, 10*time.Second,
+		channels.ClockOption[channels.BatchConfig](clk),
+		channels.BatchStatsProviderOption(provider),
+	)
+
+	in <- 1
+	in <- 2
+	clk.Advance(9 * time.Second)
+
+	window := <-out
+	require.Equal(t, windowTestTime(0), window.Start)
+	require.Equal(t, windowTestTime(10), window.End)
+	require.Equal(t, []int{1, 2}, window.Values)
+
+	stats := <-receiver.Channel()
+	require.Equal(t, channels.TumblingWindowOperator, stats.Operator)
+	require.Equal(t, uint(2), stats.BatchSize)
+	require.Equal(t, 10*time.Second, stats.Duration)
+
+	in <- 3
+	clk.Advance(10 * time.Second)
+
+	window = <-out
+	require.Equal(t, windowTestTime(10), window.Start)
+	require.Equal(t, windowTestTime(20), window.End)
+	require.Equal(t, []int{3}, window.Values)
+
+	close(in)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestTumblingWindowWritesPartialWindowOnClose(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	clk := clock.NewManual(windowTestStart)
+
+	out := channels.TumblingWindow(in, 10*time.Second,
+		channels.ClockOption[channels.BatchConfig](clk),
+	)
+
+	in <- 1
+	close(in)
+
+	window := <-out
+	require.Equal(t, windowTestTime(0), window.Start)
+	require.Equal(t, windowTestTime(10), window.End)
+	require.Equal(t, []int{1}, window.Values)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestTumblingWindowEmptyWindowsOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	clk := clock.NewManual(windowTestStart)
+
+	out := channels.TumblingWindow(in, 10*time.Second,
+		channels.ClockOption[channels.BatchConfig](clk),
+		channels.EmptyWindowsOption(true),
+		channels.ChannelCapacityOption[channels.BatchConfig](10),
+	)
+
+	in <- 1
+	clk.Advance(29 * time.Second)
+
+	require.Equal(t, []int{1}, (<-out).Values)
+
+	window := <-out
+	require.Equal(t, windowTestTime(10), window.Start)
+	require.Empty(t, window.Values)
+
+	window = <-out
+	require.Equal(t, windowTestTime(20), window.Start)
+	require.Empty(t, window.Values)
+}
+
+func TestTumblingWindowSkipsEmptyWindows(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	clk := clock.NewManual(windowTestStart)
+
+	out := channels.TumblingWindow(in, 10*time.Second,
+		channels.ClockOption[channels.BatchConfig](clk),
+	)
+
+	in <- 1
+	clk.Advance(29 * time.Second)
+
+	window := <-out
+	require.Equal(t, windowTestTime(0), window.Start)
+	require.Equal(t, []int{1}, window.Values)
+
+	in <- 2
+	clk.Advance(10 * time.Second)
+
+	window = <-out
+	require.Equal(t, windowTestTime(30), window.Start)
+	require.Equal(t, []int{2}, window.Values)
+
+	close(in)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	clk := clock.NewManual(windowTestStart)
+
+	out := channels.SlidingWindow(in, 10*time.Second, 5*time.Second,
+		channels.ClockOption[channels.BatchConfig](clk),
+	)
+
+	// windows end every 5 seconds, and contain the values from the previous 10 seconds
+	in <- 1
+	clk.Advance(4 * time.Second)
+
+	window := <-out
+	require.Equal(t, windowTestTime(-5), window.Start)
+	require.Equal(t, windowTestTime(5), window.End)
+	require.Equal(t, []int{1}, window.Values)
+
+	in <- 2
+	clk.Advance(5 * time.Second)
+
+	window = <-out
+	require.Equal(t, windowTestTime(0), window.Start)
+	require.Equal(t, windowTestTime(10), window.End)
+	require.Equal(t, []int{1, 2}, window.Values)
+
+	clk.Advance(5 * time.Second)
+
+	window = <-out
+	require.Equal(t, windowTestTime(5), window.Start)
+	require.Equal(t, windowTestTime(15), window.End)
+	require.Equal(t, []int{2}, window.Values)
+
+	in <- 3
+	close(in)
+
+	window = <-out
+	require.Equal(t, windowTestTime(10), window.Start)
+	require.Equal(t, windowTestTime(20), window.End)
+	require.Equal(t, []int{3}, window.Values)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestSlidingWindowContextOption(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, receiver := providers.NewProvider[error](1)
+	defer provider.Close()
+
+	out := channels.SlidingWindow(in, 10*time.Second, 5*time.Second,
+		channels.ContextOption[channels.BatchConfig](ctx),
+		channels.CancellationProviderOption[channels.BatchConfig](provider),
+	)
+
+	cancel()
+	require.ErrorIs(t, <-receiver.Channel(), context.Canceled)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestWindowPanicsWithNonPositiveDuration(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	defer close(in)
+
+	require.Panics(t, func() { channels.TumblingWindow(in, 0) })
+	require.Panics(t, func() { channels.SlidingWindow(in, time.Second, 0) })
+}
@@ -0,0 +1,61 @@
+package channels
+
+// WithLatestFrom reads values from the primary input channel, and writes a Pair of each
+// value and the latest value read from the secondary input channel to the output channel.
+// Values from the primary input channel are dropped until a value has been read from the
+// secondary input channel, and values from the secondary input channel are never written
+// on their own.  When the secondary input channel is closed, its latest value continues
+// to be paired with values from the primary input channel.  The output channel is
+// unbuffered by default, and will be closed when the primary input channel is closed.
+func WithLatestFrom[T1 any, T2 any](primary <-chan T1, secondary <-chan T2, opts ...Option[CombineConfig]) <-chan Pair[T1, T2] {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan Pair[T1, T2], cfg.capacity)
+	operator := cfg.operator.or(WithLatestFromOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+
+	drainOnStop(lifecycle, primary)
+	drainOnStop(lifecycle, secondary)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		var latest T2
+		var hasLatest bool
+
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case in, ok := <-secondary:
+				if !ok {
+					secondary = nil
+					continue
+				}
+				latest = in
+				hasLatest = true
+			case in, ok := <-primary:
+				if !ok {
+					return
+				}
+
+				if hasLatest && !send(ctx, outc, Pair[T1, T2]{First: in, Second: latest}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return outc
+}
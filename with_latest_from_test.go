@@ -0,0 +1,69 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonabc/channels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLatestFrom(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan int)
+	config := make(chan string)
+
+	out := channels.WithLatestFrom(events, config)
+	require.Equal(t, 0, cap(out))
+
+	// primary values are dropped until the secondary input has a value
+	events <- 1
+	config <- "v1"
+	events <- 2
+	require.Equal(t, channels.Pair[int, string]{First: 2, Second: "v1"}, <-out)
+
+	config <- "v2"
+	config <- "v3"
+	events <- 3
+	require.Equal(t, channels.Pair[int, string]{First: 3, Second: "v3"}, <-out)
+
+	// the latest value from a closed secondary input continues to be paired
+	close(config)
+	events <- 4
+	require.Equal(t, channels.Pair[int, string]{First: 4, Second: "v3"}, <-out)
+
+	close(events)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestWithLatestFromClosesWithPrimaryInput(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan int)
+	config := make(chan string)
+	defer close(config)
+
+	out := channels.WithLatestFrom(events, config)
+	close(events)
+
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestWithLatestFromContextOption(t *testing.T) {
+	t.Parallel()
+
+	inc1 := make(chan int)
+	inc2 := make(chan int)
+	defer close(inc1)
+	defer close(inc2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.WithLatestFrom(inc1, inc2, channels.ContextOption[channels.CombineConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}
@@ -0,0 +1,43 @@
+package channels
+
+// Zip reads a value from each input channel in turn, and writes a Pair of the nth
+// value read from each input channel to the output channel.  Values are paired in
+// the order they are read, so a faster input channel waits for the slower input
+// channel.  The output channel is unbuffered by default, and will be closed when
+// either input channel is closed.  A value read from the first input channel is
+// dropped if the second input channel is closed before a value is read from it.
+func Zip[T1 any, T2 any](inc1 <-chan T1, inc2 <-chan T2, opts ...Option[CombineConfig]) <-chan Pair[T1, T2] {
+	cfg := parseOpts(opts...)
+
+	outc := make(chan Pair[T1, T2], cfg.capacity)
+	operator := cfg.operator.or(ZipOperator)
+	name := cfg.name
+	panics := newPanicHandler(operator, name, cfg.panicProvider, cfg.panicInfoProvider, StopOnPanic, cfg.clock)
+	lifecycle := newLifecycle(operator, name, cfg.lifecycleProvider, cfg.stopHandle, cfg.clock)
+	cancellationProvider := cfg.cancellationProvider
+	ctx := lifecycle.bind(contextOrBackground(cfg.ctx))
+
+	drainOnStop(lifecycle, inc1)
+	drainOnStop(lifecycle, inc2)
+	lifecycle.start()
+	go func() {
+		defer lifecycle.stop(ctx)
+		defer tryProvideCancellation(ctx, cancellationProvider)
+		defer close(outc)
+		defer panics.handle()
+
+		for {
+			first, ok := receive(ctx, inc1)
+			if !ok {
+				return
+			}
+
+			second, ok := receive(ctx, inc2)
+			if !ok || !send(ctx, outc, Pair[T1, T2]{First: first, Second: second}) {
+				return
+			}
+		}
+	}()
+
+	return outc
+}
@@ -0,0 +1,66 @@
+package channels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonabc/channels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	numbers := make(chan int, 3)
+	letters := make(chan string, 2)
+
+	numbers <- 1
+	numbers <- 2
+	numbers <- 3
+	letters <- "a"
+	letters <- "b"
+	close(numbers)
+	close(letters)
+
+	out := channels.Zip(numbers, letters)
+	require.Equal(t, 0, cap(out))
+
+	results := []channels.Pair[int, string]{}
+	for pair := range out {
+		results = append(results, pair)
+	}
+
+	// the third number is dropped when letters is closed
+	require.Equal(t, []channels.Pair[int, string]{
+		{First: 1, Second: "a"},
+		{First: 2, Second: "b"},
+	}, results)
+}
+
+func TestZipChannelCapacityOption(t *testing.T) {
+	t.Parallel()
+
+	inc1 := make(chan int)
+	inc2 := make(chan int)
+	defer close(inc1)
+	defer close(inc2)
+
+	out := channels.Zip(inc1, inc2, channels.ChannelCapacityOption[channels.CombineConfig](5))
+	require.Equal(t, 5, cap(out))
+}
+
+func TestZipContextOption(t *testing.T) {
+	t.Parallel()
+
+	inc1 := make(chan int)
+	inc2 := make(chan int)
+	defer close(inc1)
+	defer close(inc2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := channels.Zip(inc1, inc2, channels.ContextOption[channels.CombineConfig](ctx))
+	cancel()
+
+	_, ok := <-out
+	require.False(t, ok)
+}